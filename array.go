@@ -0,0 +1,82 @@
+package bunutils
+
+import (
+	"fmt"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// CondArrayContains is the Condition counterpart of WhereArrayContains.
+func CondArrayContains(col string, values any) Condition {
+	return arrayCondition(col, "@>", values)
+}
+
+// CondArrayContainedBy is the Condition counterpart of WhereArrayContainedBy.
+func CondArrayContainedBy(col string, values any) Condition {
+	return arrayCondition(col, "<@", values)
+}
+
+// CondArrayOverlaps is the Condition counterpart of WhereArrayOverlaps.
+func CondArrayOverlaps(col string, values any) Condition {
+	return arrayCondition(col, "&&", values)
+}
+
+// CondAnyEqual is the Condition counterpart of WhereAnyEqual.
+func CondAnyEqual(col string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("? = ANY(?TableAlias.?)", value, column(q, col))
+	}
+}
+
+// CondArrayLength is the Condition counterpart of WhereArrayLength.
+func CondArrayLength(col string, op CompareOp, length int) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		if !op.Valid() {
+			return setQueryErr(q, fmt.Errorf("bunutils: unsupported array length operator %q", op))
+		}
+		return q.Where("cardinality(?TableAlias.?) "+string(op)+" ?", column(q, col), length)
+	}
+}
+
+func arrayCondition(col string, op string, values any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? "+op+" ?", column(q, col), pgdialect.Array(values))
+	}
+}
+
+// WhereArrayContains checks that the array column contains all the values (@>).
+//
+// Note: PostgreSQL only.
+func WhereArrayContains(col string, values any) Selector {
+	return CondArrayContains(col, values).Select
+}
+
+// WhereArrayContainedBy checks that all elements of the array column are among the values (<@).
+//
+// Note: PostgreSQL only.
+func WhereArrayContainedBy(col string, values any) Selector {
+	return CondArrayContainedBy(col, values).Select
+}
+
+// WhereArrayOverlaps checks that the array column has at least one of the values (&&).
+//
+// Note: PostgreSQL only.
+func WhereArrayOverlaps(col string, values any) Selector {
+	return CondArrayOverlaps(col, values).Select
+}
+
+// WhereAnyEqual checks that the array column has an element equal to the value (? = ANY(col)).
+//
+// Note: PostgreSQL only.
+func WhereAnyEqual(col string, value any) Selector {
+	return CondAnyEqual(col, value).Select
+}
+
+// WhereArrayLength compares the number of elements in the array column with length.
+// Unlike array_length, cardinality returns 0 for empty arrays, so they are matched by OpEqual and 0.
+//
+// Note: PostgreSQL only.
+func WhereArrayLength(col string, op CompareOp, length int) Selector {
+	return CondArrayLength(col, op, length).Select
+}
@@ -0,0 +1,93 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWhereArrayContains(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	selector := WhereArrayContains("tags", []string{"go", "sql"})
+
+	result := selector(query)
+	sql := result.String()
+
+	if !strings.Contains(sql, `"test_model"."tags" @> '{"go","sql"}'`) {
+		t.Errorf("WhereArrayContains() should add @> condition, got %s", sql)
+	}
+}
+
+func TestWhereArrayContainedBy(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	selector := WhereArrayContainedBy("roles", []string{"admin", "owner"})
+
+	result := selector(query)
+	sql := result.String()
+
+	if !strings.Contains(sql, `"test_model"."roles" <@ '{"admin","owner"}'`) {
+		t.Errorf("WhereArrayContainedBy() should add <@ condition, got %s", sql)
+	}
+}
+
+func TestWhereArrayOverlaps(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	selector := WhereArrayOverlaps("scores", []int{1, 2, 3})
+
+	result := selector(query)
+	sql := result.String()
+
+	if !strings.Contains(sql, `"test_model"."scores" && '{1,2,3}'`) {
+		t.Errorf("WhereArrayOverlaps() should add && condition, got %s", sql)
+	}
+}
+
+func TestWhereAnyEqual(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	selector := WhereAnyEqual("tags", "go")
+
+	result := selector(query)
+	sql := result.String()
+
+	if !strings.Contains(sql, `'go' = ANY("test_model"."tags")`) {
+		t.Errorf("WhereAnyEqual() should add ANY condition, got %s", sql)
+	}
+}
+
+func TestWhereArrayLength(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	t.Run("valid operator", func(t *testing.T) {
+		query := db.NewSelect().Model((*testModel)(nil))
+		selector := WhereArrayLength("tags", OpGreaterOrEqual, 2)
+
+		result := selector(query)
+		sql := result.String()
+
+		if !strings.Contains(sql, `cardinality("test_model"."tags") >= 2`) {
+			t.Errorf("WhereArrayLength() should add cardinality condition, got %s", sql)
+		}
+	})
+
+	t.Run("invalid operator", func(t *testing.T) {
+		query := db.NewSelect().Model((*testModel)(nil))
+		selector := WhereArrayLength("tags", CompareOp("; DROP TABLE test"), 2)
+
+		result := selector(query)
+		if _, err := result.AppendQuery(db.Formatter(), nil); err == nil {
+			t.Error("WhereArrayLength() should fail with invalid operator")
+		}
+	})
+}
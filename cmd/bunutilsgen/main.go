@@ -0,0 +1,610 @@
+// Command bunutilsgen generates typed columns of bun models for bunutils selectors.
+//
+// It reads the structs with bun tags of the package in the current directory and writes
+// bunutils_gen.go with column name constants, typed columns and an Order of each model:
+//
+//	//go:generate go run github.com/nesymno/bunutils/cmd/bunutilsgen -type User,Order
+//
+//	db.NewSelect().Model(&users).Apply(
+//	    UserCols.Email.Eq("x"),
+//	    UserCols.CreatedAt.After(t),
+//	    UserCols.CreatedAt.Desc(),
+//	)
+//
+// String fields get bunutils.StringColumn, numbers bunutils.NumberColumn, time.Time fields
+// bunutils.TimeColumn, bools bunutils.BoolColumn and other types bunutils.ValueColumn.
+// Relations, bun:"-" and scanonly fields are skipped. Fields of embedded structs of the package and of
+// bun:"embed:prefix_" fields are included, embedded structs of other packages are reported as errors.
+//
+// Order keys are sort_by values of clients, so they are read from the existing output file and kept
+// when it is regenerated: new columns get the next keys, reordering fields doesn't change them.
+// Generated identifiers which conflict with declarations of the package are reported as errors.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated model types, all structs with bun tags if empty")
+	output := flag.String("output", "bunutils_gen.go", "output file name")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("bunutilsgen: ")
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	src, err := generate(dir, names, *output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type model struct {
+	Name   string
+	Fields []field
+}
+
+type field struct {
+	Name   string
+	Column string
+	// Type is the typed column type, e.g. bunutils.NumberColumn[int64].
+	Type string
+	// Key is the key of the column in the Order.
+	Key int
+}
+
+// structDecl is a struct type of the package with the imports of its file.
+type structDecl struct {
+	st      *ast.StructType
+	imports map[string]string
+}
+
+type generator struct {
+	structs map[string]structDecl
+	// imports maps import names to paths of the types used by the generated code.
+	imports map[string]string
+	// fileImports maps import names to paths of the file being processed.
+	fileImports map[string]string
+}
+
+// generate returns the source of the typed columns of the models in the package directory.
+// All structs with bun tags, which are not embedded into other structs, are models if names is empty.
+func generate(dir string, names []string, output string) ([]byte, error) {
+	files, err := parsePackage(dir, output)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	g := &generator{structs: make(map[string]structDecl), imports: make(map[string]string)}
+	for _, file := range files {
+		imports := fileImports(file)
+		for name, st := range fileStructs(file) {
+			g.structs[name] = structDecl{st: st, imports: imports}
+		}
+	}
+	embedded := embeddedStructs(g.structs)
+	keys, err := orderKeys(filepath.Join(dir, output))
+	if err != nil {
+		return nil, err
+	}
+
+	var models []model
+	found := make(map[string]bool)
+	for _, file := range files {
+		g.fileImports = fileImports(file)
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil {
+					continue
+				}
+				if names != nil && !slices.Contains(names, ts.Name.Name) {
+					continue
+				}
+				if names == nil && (embedded[ts.Name.Name] || !g.isModel(st)) {
+					continue
+				}
+
+				m := model{Name: ts.Name.Name}
+				if err := g.addFields(&m, st, "", ""); err != nil {
+					return nil, fmt.Errorf("%s: %w", ts.Name.Name, err)
+				}
+				m.assignKeys(keys[m.Name])
+				models = append(models, m)
+				found[ts.Name.Name] = true
+			}
+		}
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+	}
+	if err := checkConflicts(files, models); err != nil {
+		return nil, err
+	}
+
+	var imports []string
+	for name, path := range g.imports {
+		imports = append(imports, importSpec(name, path))
+	}
+	slices.Sort(imports)
+
+	var b bytes.Buffer
+	err = fileTemplate.Execute(&b, map[string]any{
+		"Package": files[0].Name.Name,
+		"Imports": imports,
+		"Models":  models,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
+
+// parsePackage parses the non-test Go files of the directory except the output file, sorted by name.
+func parsePackage(dir string, output string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func fileStructs(file *ast.File) map[string]*ast.StructType {
+	structs := make(map[string]*ast.StructType)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok {
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				structs[ts.Name.Name] = st
+			}
+		}
+		return true
+	})
+	return structs
+}
+
+// embeddedStructs returns the names of the structs which are embedded into other structs of the package,
+// they are parts of models rather than models.
+func embeddedStructs(structs map[string]structDecl) map[string]bool {
+	embedded := make(map[string]bool)
+	for _, decl := range structs {
+		for _, f := range decl.st.Fields.List {
+			_, isEmbed := tagOption(fieldTag(f).Get("bun"), "embed")
+			if ident, ok := derefType(f.Type).(*ast.Ident); ok && (len(f.Names) == 0 || isEmbed) {
+				embedded[ident.Name] = true
+			}
+		}
+	}
+	return embedded
+}
+
+func derefType(expr ast.Expr) ast.Expr {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X
+	}
+	return expr
+}
+
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = p
+	}
+	return imports
+}
+
+func importSpec(name, p string) string {
+	if path.Base(p) == name {
+		return strconv.Quote(p)
+	}
+	return name + " " + strconv.Quote(p)
+}
+
+// isModel reports whether the struct embeds bun.BaseModel or has fields with bun tags.
+func (g *generator) isModel(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if g.isBaseModel(f.Type) {
+			return true
+		}
+		if f.Tag != nil {
+			if _, ok := fieldTag(f).Lookup("bun"); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *generator) isBaseModel(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "BaseModel" {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && g.fileImports[x.Name] == "github.com/uptrace/bun"
+}
+
+func fieldTag(f *ast.Field) reflect.StructTag {
+	if f.Tag == nil {
+		return ""
+	}
+	tag, _ := strconv.Unquote(f.Tag.Value)
+	return reflect.StructTag(tag)
+}
+
+// addFields adds the columns of the struct fields to the model. Names of the fields of bun:"embed:prefix_"
+// fields are prefixed with the field name and their columns with the prefix.
+func (g *generator) addFields(m *model, st *ast.StructType, namePrefix, colPrefix string) error {
+	for _, f := range st.Fields.List {
+		tag := fieldTag(f).Get("bun")
+		if tag == "-" || g.isBaseModel(f.Type) {
+			continue
+		}
+		column, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(column, ":") {
+			// The tag has no name, e.g. bun:"rel:has-many,join:id=user_id".
+			column, opts = "", tag
+		}
+		if isSkipped(opts) {
+			continue
+		}
+
+		prefix, isEmbed := tagOption(opts, "embed")
+		if len(f.Names) == 0 || isEmbed {
+			// Fields of embedded structs are columns of the model.
+			name := namePrefix
+			if len(f.Names) > 0 {
+				name += f.Names[0].Name
+			}
+			if err := g.addEmbeddedFields(m, f.Type, name, colPrefix+prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			col := column
+			if col == "" {
+				col = underscore(name.Name)
+			}
+
+			typ, err := g.columnType(f.Type)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", name.Name, err)
+			}
+			m.Fields = append(m.Fields, field{Name: namePrefix + name.Name, Column: colPrefix + col, Type: typ})
+		}
+	}
+	return nil
+}
+
+func (g *generator) addEmbeddedFields(m *model, expr ast.Expr, namePrefix, colPrefix string) error {
+	ident, ok := derefType(expr).(*ast.Ident)
+	if !ok {
+		return fmt.Errorf("embedded %s: only structs of the package are supported", types.ExprString(expr))
+	}
+	decl, ok := g.structs[ident.Name]
+	if !ok {
+		return fmt.Errorf("embedded %s: struct type not found", ident.Name)
+	}
+
+	// Field types refer to the imports of the file of the embedded struct.
+	imports := g.fileImports
+	g.fileImports = decl.imports
+	defer func() { g.fileImports = imports }()
+	return g.addFields(m, decl.st, namePrefix, colPrefix)
+}
+
+// isSkipped reports whether the bun tag options are of a relation or a field which is not a column.
+func isSkipped(opts string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		key, _, _ := strings.Cut(opt, ":")
+		switch key {
+		case "rel", "m2m", "scanonly":
+			return true
+		}
+	}
+	return false
+}
+
+// tagOption returns the value of the bun tag option, e.g. the prefix of embed:prefix_.
+func tagOption(opts string, key string) (string, bool) {
+	for _, opt := range strings.Split(opts, ",") {
+		if k, v, _ := strings.Cut(opt, ":"); k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// assignKeys sets the Order keys of the fields: the previous keys of their constants,
+// and the keys after the largest previous key for new columns. Keys start at 1, the zero
+// WhereOf.SortBy means no sort with DefaultSorts.
+func (m *model) assignKeys(prev map[string]int) {
+	next := 1
+	for _, key := range prev {
+		next = max(next, key+1)
+	}
+	for i := range m.Fields {
+		if key, ok := prev[m.constName(m.Fields[i])]; ok {
+			m.Fields[i].Key = key
+			continue
+		}
+		m.Fields[i].Key = next
+		next++
+	}
+}
+
+func (m *model) constName(f field) string {
+	return m.Name + "Column" + f.Name
+}
+
+// orderKeys returns the Order keys of the column constants of each model in the existing output file.
+func orderKeys(filename string) (map[string]map[string]int, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.SkipObjectResolution)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]map[string]int)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Names) != 1 || len(vs.Values) != 1 || !strings.HasSuffix(vs.Names[0].Name, "Order") {
+				continue
+			}
+			lit, ok := vs.Values[0].(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+
+			modelKeys := make(map[string]int)
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.BasicLit)
+				value, ok2 := kv.Value.(*ast.Ident)
+				if !ok || !ok2 || key.Kind != token.INT {
+					continue
+				}
+				n, err := strconv.Atoi(key.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", filename, err)
+				}
+				modelKeys[value.Name] = n
+			}
+			keys[strings.TrimSuffix(vs.Names[0].Name, "Order")] = modelKeys
+		}
+	}
+	return keys, nil
+}
+
+// checkConflicts reports generated identifiers which are declared in the package or generated twice.
+func checkConflicts(files []*ast.File, models []model) error {
+	declared := make(map[string]bool)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					declared[decl.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						declared[spec.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							declared[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	generated := make(map[string]string)
+	for _, m := range models {
+		names := []string{m.Name + "Cols", m.Name + "Order"}
+		for _, f := range m.Fields {
+			names = append(names, m.constName(f))
+		}
+		for _, name := range names {
+			if declared[name] {
+				return fmt.Errorf("%s: generated %s conflicts with a declaration of the package", m.Name, name)
+			}
+			if other, ok := generated[name]; ok {
+				return fmt.Errorf("%s: generated %s conflicts with the one of %s", m.Name, name, other)
+			}
+			generated[name] = m.Name
+		}
+	}
+	return nil
+}
+
+// columnType returns the typed column type of the field type.
+func (g *generator) columnType(expr ast.Expr) (string, error) {
+	expr = derefType(expr)
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return "bunutils.StringColumn", nil
+		case "bool":
+			return "bunutils.BoolColumn", nil
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64":
+			return "bunutils.NumberColumn[" + t.Name + "]", nil
+		}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok && g.fileImports[x.Name] == "time" && t.Sel.Name == "Time" {
+			return "bunutils.TimeColumn", nil
+		}
+	}
+
+	if err := g.addImports(expr); err != nil {
+		return "", err
+	}
+	return "bunutils.ValueColumn[" + types.ExprString(expr) + "]", nil
+}
+
+// addImports adds the imports of the packages referred to by the type.
+func (g *generator) addImports(expr ast.Expr) error {
+	var err error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		p, ok := g.fileImports[x.Name]
+		if !ok {
+			err = fmt.Errorf("unknown package %s", x.Name)
+			return false
+		}
+		if prev, ok := g.imports[x.Name]; ok && prev != p {
+			err = fmt.Errorf("package name %s refers to %s and %s", x.Name, prev, p)
+			return false
+		}
+		g.imports[x.Name] = p
+		return false
+	})
+	return err
+}
+
+// underscore converts the field name to the column name the same way as bun, e.g. UserID to user_id.
+func underscore(s string) string {
+	isUpper := func(c byte) bool { return c >= 'A' && c <= 'Z' }
+	isLower := func(c byte) bool { return c >= 'a' && c <= 'z' }
+
+	b := make([]byte, 0, len(s)+5)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isUpper(c) {
+			b = append(b, c)
+			continue
+		}
+		if i > 0 && i+1 < len(s) && (isLower(s[i-1]) || isLower(s[i+1])) {
+			b = append(b, '_')
+		}
+		b = append(b, c+'a'-'A')
+	}
+	return string(b)
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by bunutilsgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+
+	"github.com/nesymno/bunutils"
+)
+{{range $m := .Models}}
+// Column names of {{$m.Name}}.
+const (
+{{- range $m.Fields}}
+	{{$m.Name}}Column{{.Name}} = {{printf "%q" .Column}}
+{{- end}}
+)
+
+// {{$m.Name}}Cols are the typed columns of {{$m.Name}}.
+var {{$m.Name}}Cols = struct {
+{{- range $m.Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}{
+{{- range $m.Fields}}
+	{{.Name}}: {{$m.Name}}Column{{.Name}},
+{{- end}}
+}
+
+// {{$m.Name}}Order is the Order of {{$m.Name}} columns. Keys are kept when the file is regenerated,
+// new columns get the next keys.
+var {{$m.Name}}Order = bunutils.Order{
+{{- range $m.Fields}}
+	{{.Key}}: {{$m.Name}}Column{{.Name}},
+{{- end}}
+}
+{{end -}}
+`))
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "models")
+	src, err := generate(dir, nil, "bunutils_gen.go")
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	golden := filepath.Join(dir, "bunutils_gen.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(want) {
+		t.Errorf("generate() =\n%s\nwant\n%s", src, want)
+	}
+}
+
+func TestGenerate_Types(t *testing.T) {
+	dir := filepath.Join("testdata", "models")
+	src, err := generate(dir, []string{"Order"}, "bunutils_gen.go")
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	if strings.Contains(string(src), "UserCols") || !strings.Contains(string(src), "OrderCols") {
+		t.Errorf("generate() should generate only Order, got\n%s", src)
+	}
+
+	if _, err := generate(dir, []string{"Invoice"}, "bunutils_gen.go"); err == nil || !strings.Contains(err.Error(), "Invoice not found") {
+		t.Errorf("generate() error = %v, want not found error", err)
+	}
+}
+
+func writePackage(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGenerate_StableKeys(t *testing.T) {
+	dir := writePackage(t, map[string]string{
+		"models.go": `package models
+
+type Item struct {
+	Name  string ` + "`bun:\"name\"`" + `
+	Price int    ` + "`bun:\"price\"`" + `
+	ID    int64  ` + "`bun:\"id,pk\"`" + `
+}
+`,
+		"bunutils_gen.go": `package models
+
+var ItemOrder = bunutils.Order{
+	0: ItemColumnID,
+	3: ItemColumnName,
+}
+`,
+	})
+
+	src, err := generate(dir, nil, "bunutils_gen.go")
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for _, want := range []string{"0: ItemColumnID", "3: ItemColumnName", "4: ItemColumnPrice"} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generate() should keep Order keys, want %s in\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "conflict",
+			src: `package models
+
+type User struct {
+	ID int64 ` + "`bun:\"id,pk\"`" + `
+}
+
+type UserOrder struct {
+	ID int64 ` + "`bun:\"id,pk\"`" + `
+}
+`,
+			want: "generated UserOrder conflicts",
+		},
+		{
+			name: "embedded struct of another package",
+			src: `package models
+
+import "example.com/audit"
+
+type User struct {
+	ID int64 ` + "`bun:\"id,pk\"`" + `
+	*audit.Fields
+}
+`,
+			want: "embedded *audit.Fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePackage(t, map[string]string{"models.go": tt.src})
+			if _, err := generate(dir, nil, "bunutils_gen.go"); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("generate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnderscore(t *testing.T) {
+	tests := map[string]string{
+		"ID":        "id",
+		"UserID":    "user_id",
+		"CreatedAt": "created_at",
+		"HTTPCode":  "http_code",
+		"Name":      "name",
+	}
+	for in, want := range tests {
+		if got := underscore(in); got != want {
+			t.Errorf("underscore(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
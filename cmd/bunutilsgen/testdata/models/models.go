@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type Timestamps struct {
+	CreatedAt time.Time  `bun:",nullzero,notnull,default:current_timestamp"`
+	UpdatedAt *time.Time `bun:"updated"`
+}
+
+type Audit struct {
+	CreatedBy string `bun:"created_by"`
+}
+
+type Address struct {
+	City string
+	Zip  string `bun:"zip"`
+}
+
+type User struct {
+	bun.BaseModel `bun:"table:users,alias:u"`
+
+	ID       int64          `bun:"id,pk,autoincrement"`
+	Email    string         `bun:"email,notnull"`
+	Age      *int           `bun:"age"`
+	Active   bool           `bun:"active"`
+	Nickname sql.NullString `bun:"nickname"`
+	Tags     []string       `bun:"tags,array"`
+	UserID   string
+	Timestamps
+	*Audit
+	Home Address `bun:"embed:home_"`
+
+	Orders   []*Order `bun:"rel:has-many,join:id=user_id"`
+	Password string   `bun:"-"`
+	Total    int      `bun:"total,scanonly"`
+	internal string
+}
+
+type Order struct {
+	ID     int64   `bun:"id,pk"`
+	UserID int64   `bun:"user_id"`
+	Amount float64 `bun:"amount"`
+	User   *User   `bun:"rel:belongs-to,join:user_id=id"`
+}
+
+type request struct {
+	Email string `json:"email"`
+}
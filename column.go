@@ -0,0 +1,274 @@
+package bunutils
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Number is a constraint of NumberColumn values.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// StringColumn is a typed column of string values, see cmd/bunutilsgen.
+type StringColumn string
+
+// NumberColumn is a typed column of numeric values, see cmd/bunutilsgen.
+// Unlike WhereGt and the other comparison selectors, its comparisons don't skip zero values.
+type NumberColumn[T Number] string
+
+// TimeColumn is a typed column of time.Time values, see cmd/bunutilsgen.
+// Like NumberColumn, its comparisons don't skip zero times. Before and After are inclusive.
+type TimeColumn string
+
+// BoolColumn is a typed column of bool values, see cmd/bunutilsgen.
+type BoolColumn string
+
+// ValueColumn is a typed column of values of other types, see cmd/bunutilsgen.
+// Like NumberColumn, its comparisons don't skip zero values.
+type ValueColumn[T any] string
+
+// Col is a typed column declared next to the model, without code generation:
+//
+//	var Age = bunutils.Col[int]("age")
+//	db.NewSelect().Model(&users).Apply(Age.Gt(18), Age.Desc())
+//
+// Values of other types than T don't compile.
+type Col[T any] = ValueColumn[T]
+
+// orderByColumn orders by the column of the query model.
+func orderByColumn(col string, desc bool) Selector {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if desc {
+			return q.OrderExpr("?TableAlias.? DESC", column(q.QueryBuilder(), col))
+		}
+		return q.OrderExpr("?TableAlias.? ASC", column(q.QueryBuilder(), col))
+	}
+}
+
+func (c StringColumn) Name() string {
+	return string(c)
+}
+
+func (c StringColumn) Eq(value string) Selector {
+	return WhereEqual(string(c), value)
+}
+
+func (c StringColumn) Ne(value string) Selector {
+	return WhereNotEqual(string(c), value)
+}
+
+func (c StringColumn) In(values ...string) Selector {
+	return WhereIn(string(c), values)
+}
+
+func (c StringColumn) NotIn(values ...string) Selector {
+	return WhereNotIn(string(c), values)
+}
+
+func (c StringColumn) IsNull() Selector {
+	return WhereNull(string(c))
+}
+
+func (c StringColumn) IsNotNull() Selector {
+	return WhereNotNull(string(c))
+}
+
+func (c StringColumn) Contains(substr string) Selector {
+	return WhereContains(string(c), substr)
+}
+
+func (c StringColumn) Begins(substr string) Selector {
+	return WhereBegins(string(c), substr)
+}
+
+func (c StringColumn) Ends(substr string) Selector {
+	return WhereEnds(string(c), substr)
+}
+
+func (c StringColumn) Asc() Selector {
+	return orderByColumn(string(c), false)
+}
+
+func (c StringColumn) Desc() Selector {
+	return orderByColumn(string(c), true)
+}
+
+func (c NumberColumn[T]) Name() string {
+	return string(c)
+}
+
+func (c NumberColumn[T]) Eq(value T) Selector {
+	return WhereEqual(string(c), value)
+}
+
+func (c NumberColumn[T]) Ne(value T) Selector {
+	return WhereNotEqual(string(c), value)
+}
+
+func (c NumberColumn[T]) In(values ...T) Selector {
+	return WhereIn(string(c), values)
+}
+
+func (c NumberColumn[T]) NotIn(values ...T) Selector {
+	return WhereNotIn(string(c), values)
+}
+
+func (c NumberColumn[T]) IsNull() Selector {
+	return WhereNull(string(c))
+}
+
+func (c NumberColumn[T]) IsNotNull() Selector {
+	return WhereNotNull(string(c))
+}
+
+func (c NumberColumn[T]) Gt(value T) Selector {
+	return WhereGt(string(c), &value)
+}
+
+func (c NumberColumn[T]) Gte(value T) Selector {
+	return WhereGte(string(c), &value)
+}
+
+func (c NumberColumn[T]) Lt(value T) Selector {
+	return WhereLt(string(c), &value)
+}
+
+func (c NumberColumn[T]) Lte(value T) Selector {
+	return WhereLte(string(c), &value)
+}
+
+func (c NumberColumn[T]) Between(from, to T) Selector {
+	return WhereBetween(string(c), &from, &to)
+}
+
+func (c NumberColumn[T]) Asc() Selector {
+	return orderByColumn(string(c), false)
+}
+
+func (c NumberColumn[T]) Desc() Selector {
+	return orderByColumn(string(c), true)
+}
+
+func (c TimeColumn) Name() string {
+	return string(c)
+}
+
+func (c TimeColumn) Eq(t time.Time) Selector {
+	return WhereEqual(string(c), t)
+}
+
+func (c TimeColumn) Ne(t time.Time) Selector {
+	return WhereNotEqual(string(c), t)
+}
+
+func (c TimeColumn) IsNull() Selector {
+	return WhereNull(string(c))
+}
+
+func (c TimeColumn) IsNotNull() Selector {
+	return WhereNotNull(string(c))
+}
+
+func (c TimeColumn) Before(t time.Time) Selector {
+	return WhereBefore(string(c), t)
+}
+
+func (c TimeColumn) After(t time.Time) Selector {
+	return WhereAfter(string(c), t)
+}
+
+func (c TimeColumn) Between(from, to time.Time) Selector {
+	return WhereBetween(string(c), &from, &to)
+}
+
+func (c TimeColumn) Asc() Selector {
+	return orderByColumn(string(c), false)
+}
+
+func (c TimeColumn) Desc() Selector {
+	return orderByColumn(string(c), true)
+}
+
+func (c BoolColumn) Name() string {
+	return string(c)
+}
+
+func (c BoolColumn) Eq(value bool) Selector {
+	return WhereEqual(string(c), value)
+}
+
+func (c BoolColumn) IsNull() Selector {
+	return WhereNull(string(c))
+}
+
+func (c BoolColumn) IsNotNull() Selector {
+	return WhereNotNull(string(c))
+}
+
+func (c BoolColumn) Asc() Selector {
+	return orderByColumn(string(c), false)
+}
+
+func (c BoolColumn) Desc() Selector {
+	return orderByColumn(string(c), true)
+}
+
+func (c ValueColumn[T]) Name() string {
+	return string(c)
+}
+
+func (c ValueColumn[T]) Eq(value T) Selector {
+	return WhereEqual(string(c), value)
+}
+
+func (c ValueColumn[T]) Ne(value T) Selector {
+	return WhereNotEqual(string(c), value)
+}
+
+func (c ValueColumn[T]) In(values ...T) Selector {
+	return WhereIn(string(c), values)
+}
+
+func (c ValueColumn[T]) NotIn(values ...T) Selector {
+	return WhereNotIn(string(c), values)
+}
+
+func (c ValueColumn[T]) Gt(value T) Selector {
+	return WhereGt(string(c), &value)
+}
+
+func (c ValueColumn[T]) Gte(value T) Selector {
+	return WhereGte(string(c), &value)
+}
+
+func (c ValueColumn[T]) Lt(value T) Selector {
+	return WhereLt(string(c), &value)
+}
+
+func (c ValueColumn[T]) Lte(value T) Selector {
+	return WhereLte(string(c), &value)
+}
+
+func (c ValueColumn[T]) Between(from, to T) Selector {
+	return WhereBetween(string(c), &from, &to)
+}
+
+func (c ValueColumn[T]) IsNull() Selector {
+	return WhereNull(string(c))
+}
+
+func (c ValueColumn[T]) IsNotNull() Selector {
+	return WhereNotNull(string(c))
+}
+
+func (c ValueColumn[T]) Asc() Selector {
+	return orderByColumn(string(c), false)
+}
+
+func (c ValueColumn[T]) Desc() Selector {
+	return orderByColumn(string(c), true)
+}
@@ -0,0 +1,88 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedColumns(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	var (
+		name    StringColumn       = "name"
+		age     NumberColumn[int]  = "age"
+		created TimeColumn         = "created_at"
+		active  BoolColumn         = "active"
+		id      ValueColumn[int64] = "id"
+	)
+	ts := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{name: "string eq", selector: name.Eq("x"), want: `"test_model"."name" = 'x'`},
+		{name: "string in", selector: name.In("a", "b"), want: `"test_model"."name" IN ('a', 'b')`},
+		{name: "string contains", selector: name.Contains("x"), want: `"test_model"."name" ILIKE '%x%'`},
+		{name: "number gt zero", selector: age.Gt(0), want: `"test_model"."age" > 0`},
+		{name: "number between", selector: age.Between(0, 10), want: `"test_model"."age" BETWEEN 0 AND 10`},
+		{name: "time after", selector: created.After(ts), want: `"test_model"."created_at" >= '2024-01-02 00:00:00+00:00'`},
+		{name: "time between zero", selector: created.Between(time.Time{}, ts), want: `"test_model"."created_at" BETWEEN '0001-01-01 00:00:00+00:00' AND '2024-01-02 00:00:00+00:00'`},
+		{name: "bool eq", selector: active.Eq(false), want: `"test_model"."active" = FALSE`},
+		{name: "value null", selector: id.IsNull(), want: `"test_model"."id" is null`},
+		{name: "asc", selector: name.Asc(), want: `ORDER BY "test_model"."name" ASC`},
+		{name: "desc", selector: age.Desc(), want: `ORDER BY "test_model"."age" DESC`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+
+	if name.Name() != "name" {
+		t.Errorf("Name() = %q, want %q", name.Name(), "name")
+	}
+}
+
+func TestCol(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	age := Col[int]("age")
+	created := Col[time.Time]("created_at")
+	ts := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{name: "eq", selector: age.Eq(18), want: `"test_model"."age" = 18`},
+		{name: "ne", selector: age.Ne(18), want: `"test_model"."age" != 18`},
+		{name: "in", selector: age.In(1, 2), want: `"test_model"."age" IN (1, 2)`},
+		{name: "gt zero", selector: age.Gt(0), want: `"test_model"."age" > 0`},
+		{name: "lt", selector: created.Lt(ts), want: `"test_model"."created_at" < '2024-01-02 00:00:00+00:00'`},
+		{name: "between", selector: age.Between(0, 65), want: `"test_model"."age" BETWEEN 0 AND 65`},
+		{name: "is null", selector: created.IsNull(), want: `"test_model"."created_at" is null`},
+		{name: "gte", selector: age.Gte(18), want: `"test_model"."age" >= 18`},
+		{name: "is not null", selector: created.IsNotNull(), want: `"test_model"."created_at" is not null`},
+		{name: "asc", selector: age.Asc(), want: `ORDER BY "test_model"."age" ASC`},
+		{name: "desc", selector: created.Desc(), want: `ORDER BY "test_model"."created_at" DESC`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,540 @@
+package bunutils
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// Condition is a WHERE condition that can be applied to SELECT, UPDATE and DELETE queries.
+// It is built on top of bun.QueryBuilder, so the same filter can be reused for reads and bulk writes:
+//
+//	cond := CondEqual("status", "expired")
+//	db.NewSelect().Model(&items).Apply(cond.Select)
+//	db.NewUpdate().Model(&item).Set("archived = true").Apply(cond.Update)
+//	db.NewDelete().Model(&item).Apply(cond.Delete)
+type Condition func(bun.QueryBuilder) bun.QueryBuilder
+
+// Select applies the Condition to a SELECT query. The method value c.Select can be used as a Selector.
+func (c Condition) Select(q *bun.SelectQuery) *bun.SelectQuery {
+	if c == nil {
+		return q
+	}
+	return q.ApplyQueryBuilder(c)
+}
+
+// Update applies the Condition to an UPDATE query.
+func (c Condition) Update(q *bun.UpdateQuery) *bun.UpdateQuery {
+	if c == nil {
+		return q
+	}
+	return q.ApplyQueryBuilder(c)
+}
+
+// Delete applies the Condition to a DELETE query.
+func (c Condition) Delete(q *bun.DeleteQuery) *bun.DeleteQuery {
+	if c == nil {
+		return q
+	}
+	return q.ApplyQueryBuilder(c)
+}
+
+// Selector returns the Condition as a Selector.
+func (c Condition) Selector() Selector {
+	if c == nil {
+		return nil
+	}
+	return c.Select
+}
+
+// CondApplyIf is the Condition counterpart of ApplyIf.
+func CondApplyIf(cond bool, conds ...Condition) Condition {
+	if !cond {
+		return nil
+	}
+	return CondApply(conds...)
+}
+
+// CondApply is the Condition counterpart of Apply.
+// It combines multiple Conditions into one.
+func CondApply(conds ...Condition) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		for _, cond := range conds {
+			if cond != nil {
+				q = cond(q)
+			}
+		}
+		return q
+	}
+}
+
+// CondOrGroup is the Condition counterpart of OrGroup.
+func CondOrGroup(conds ...Condition) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.WhereGroup(" OR ", CondApply(conds...))
+	}
+}
+
+// CondAndGroup is the Condition counterpart of AndGroup.
+func CondAndGroup(conds ...Condition) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.WhereGroup(" AND ", CondApply(conds...))
+	}
+}
+
+// CondOr is the Condition counterpart of Or.
+func CondOr(conds ...Condition) Condition {
+	return CondAndGroup(Map(conds, func(c Condition, _ int) Condition {
+		return CondOrGroup(c)
+	})...)
+}
+
+// CondNot is the Condition counterpart of Not.
+func CondNot(conds ...Condition) Condition {
+	apply := CondApply(conds...)
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		var added bool
+		apply(whereProbe{QueryBuilder: q, added: &added})
+		if !added {
+			return q
+		}
+		// bun drops the separator of the first condition of WHERE and of each group, so NOT can't
+		// be the separator of the group itself. The empty condition goes first and takes the drop:
+		// its " AND " is written only after other conditions, and it renders nothing on its own.
+		// Without conditions in the group it would be left alone, so the probe above comes first.
+		return q.Where("").WhereGroup("NOT ", apply)
+	}
+}
+
+// whereProbe is a bun.QueryBuilder which records that conditions are added instead of adding them.
+// Conditions which use the query of Unwrap are counted as added, they may add conditions to it.
+type whereProbe struct {
+	bun.QueryBuilder
+	added *bool
+}
+
+func (p whereProbe) Where(string, ...any) bun.QueryBuilder {
+	*p.added = true
+	return p
+}
+
+func (p whereProbe) WhereOr(string, ...any) bun.QueryBuilder {
+	*p.added = true
+	return p
+}
+
+func (p whereProbe) WherePK(...string) bun.QueryBuilder {
+	*p.added = true
+	return p
+}
+
+func (p whereProbe) WhereGroup(_ string, fn func(bun.QueryBuilder) bun.QueryBuilder) bun.QueryBuilder {
+	fn(p)
+	return p
+}
+
+func (p whereProbe) WhereDeleted() bun.QueryBuilder {
+	return p
+}
+
+func (p whereProbe) WhereAllWithDeleted() bun.QueryBuilder {
+	return p
+}
+
+func (p whereProbe) Unwrap() any {
+	*p.added = true
+	return p.QueryBuilder.Unwrap()
+}
+
+// CondJsonbEqual is the Condition counterpart of WhereJsonbEqual.
+func CondJsonbEqual(col string, field string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.?->>? = ?", column(q, col), field, value)
+	}
+}
+
+// CondJsonbPathEqual is the Condition counterpart of WhereJsonbPathEqual.
+func CondJsonbPathEqual(col string, path []string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		expr, args := jsonbPathExpression(q, col, path, true)
+		return q.Where(expr+" = ?", append(args, value)...)
+	}
+}
+
+// CondJsonbObjectsArrayKeyValueEqual is the Condition counterpart of WhereJsonbObjectsArrayKeyValueEqual.
+func CondJsonbObjectsArrayKeyValueEqual(col string, key, field string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where(
+			`?TableAlias.? -> ? @> jsonb_build_array(jsonb_build_object(?::text, ?::text))`,
+			column(q, col), key, field, value,
+		)
+	}
+}
+
+// CondJsonbPathObjectsArrayKeyValueEqual is the Condition counterpart of WhereJsonbPathObjectsArrayKeyValueEqual.
+func CondJsonbPathObjectsArrayKeyValueEqual(col string, path []string, field string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		expr, args := jsonbPathExpression(q, col, path, false)
+		return q.Where(
+			expr+" @> jsonb_build_array(jsonb_build_object(?::text, ?::text))",
+			append(args, field, value)...,
+		)
+	}
+}
+
+func CondEqual(col string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? = ?", column(q, col), value)
+	}
+}
+
+func CondNull(col string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? is null", column(q, col))
+	}
+}
+
+func CondNotNull(col string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? is not null", column(q, col))
+	}
+}
+
+func CondNotEqual(col string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? != ?", column(q, col), value)
+	}
+}
+
+func CondIn(col string, values any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? IN (?)", column(q, col), bun.In(values))
+	}
+}
+
+func CondNotIn(col string, values any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? NOT IN (?)", column(q, col), bun.In(values))
+	}
+}
+
+// CondContains is the Condition counterpart of WhereContains.
+func CondContains(col string, substr string) Condition {
+	return likeCondition(col, substr, likeContains, false)
+}
+
+// CondBegins is the Condition counterpart of WhereBegins.
+func CondBegins(col string, substr string) Condition {
+	return likeCondition(col, substr, likeBegins, false)
+}
+
+// CondEnds is the Condition counterpart of WhereEnds.
+func CondEnds(col string, substr string) Condition {
+	return likeCondition(col, substr, likeEnds, false)
+}
+
+// CondContainsCaseSensitive is the Condition counterpart of WhereContainsCaseSensitive.
+func CondContainsCaseSensitive(col string, substr string) Condition {
+	return likeCondition(col, substr, likeContains, true)
+}
+
+// CondBeginsCaseSensitive is the Condition counterpart of WhereBeginsCaseSensitive.
+func CondBeginsCaseSensitive(col string, substr string) Condition {
+	return likeCondition(col, substr, likeBegins, true)
+}
+
+// CondEndsCaseSensitive is the Condition counterpart of WhereEndsCaseSensitive.
+func CondEndsCaseSensitive(col string, substr string) Condition {
+	return likeCondition(col, substr, likeEnds, true)
+}
+
+func CondBefore(col string, t time.Time) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? <= ?", column(q, col), t)
+	}
+}
+
+func CondAfter(col string, t time.Time) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? >= ?", column(q, col), t)
+	}
+}
+
+// Bounds defines which ends of a range are included by CondRange and CondNotRange.
+type Bounds int
+
+const (
+	// BoundsInclusive includes both ends of the range: from <= col <= to.
+	BoundsInclusive Bounds = iota
+	// BoundsExclusive excludes both ends of the range: from < col < to.
+	BoundsExclusive
+	// BoundsLowerInclusive includes only the lower end of the range: from <= col < to.
+	BoundsLowerInclusive
+	// BoundsUpperInclusive includes only the upper end of the range: from < col <= to.
+	BoundsUpperInclusive
+)
+
+func (b Bounds) operators() (lower, upper string) {
+	switch b {
+	case BoundsExclusive:
+		return ">", "<"
+	case BoundsLowerInclusive:
+		return ">=", "<"
+	case BoundsUpperInclusive:
+		return ">", "<="
+	default:
+		return ">=", "<="
+	}
+}
+
+// CondGt adds col > value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func CondGt(col string, value any) Condition {
+	return compareCondition(col, ">", value)
+}
+
+// CondGte adds col >= value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func CondGte(col string, value any) Condition {
+	return compareCondition(col, ">=", value)
+}
+
+// CondLt adds col < value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func CondLt(col string, value any) Condition {
+	return compareCondition(col, "<", value)
+}
+
+// CondLte adds col <= value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func CondLte(col string, value any) Condition {
+	return compareCondition(col, "<=", value)
+}
+
+// CondBetween adds inclusive col BETWEEN from AND to condition.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func CondBetween(col string, from, to any) Condition {
+	return CondRange(col, from, to, BoundsInclusive)
+}
+
+// CondNotBetween adds inclusive col NOT BETWEEN from AND to condition.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func CondNotBetween(col string, from, to any) Condition {
+	return CondNotRange(col, from, to, BoundsInclusive)
+}
+
+// CondRange checks that col is within the range from..to, bounds define which ends are included.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func CondRange(col string, from, to any, bounds Bounds) Condition {
+	lower, upper := bounds.operators()
+	skipFrom, skipTo := isZeroBound(from), isZeroBound(to)
+
+	switch {
+	case skipFrom && skipTo:
+		return nil
+	case skipFrom:
+		return compareCondition(col, upper, to)
+	case skipTo:
+		return compareCondition(col, lower, from)
+	}
+
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		if bounds == BoundsInclusive {
+			return q.Where("?TableAlias.? BETWEEN ? AND ?", column(q, col), from, to)
+		}
+		return q.Where(
+			"?TableAlias.? "+lower+" ? AND ?TableAlias.? "+upper+" ?",
+			column(q, col), from, column(q, col), to,
+		)
+	}
+}
+
+// CondNotRange checks that col is outside the range from..to, bounds define which ends belong to the range.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func CondNotRange(col string, from, to any, bounds Bounds) Condition {
+	lower, upper := bounds.operators()
+	lower, upper = negateOperator(lower), negateOperator(upper)
+	skipFrom, skipTo := isZeroBound(from), isZeroBound(to)
+
+	switch {
+	case skipFrom && skipTo:
+		return nil
+	case skipFrom:
+		return compareCondition(col, upper, to)
+	case skipTo:
+		return compareCondition(col, lower, from)
+	}
+
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		if bounds == BoundsInclusive {
+			return q.Where("?TableAlias.? NOT BETWEEN ? AND ?", column(q, col), from, to)
+		}
+		return q.Where(
+			"?TableAlias.? "+lower+" ? OR ?TableAlias.? "+upper+" ?",
+			column(q, col), from, column(q, col), to,
+		)
+	}
+}
+
+// CompareOp is a comparison operator.
+type CompareOp string
+
+const (
+	OpEqual          CompareOp = "="
+	OpNotEqual       CompareOp = "!="
+	OpLess           CompareOp = "<"
+	OpLessOrEqual    CompareOp = "<="
+	OpGreater        CompareOp = ">"
+	OpGreaterOrEqual CompareOp = ">="
+)
+
+// Valid reports whether op is one of the supported comparison operators.
+func (op CompareOp) Valid() bool {
+	switch op {
+	case OpEqual, OpNotEqual, OpLess, OpLessOrEqual, OpGreater, OpGreaterOrEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+func compareCondition(col string, op string, value any) Condition {
+	if isZeroBound(value) {
+		return nil
+	}
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? "+op+" ?", column(q, col), value)
+	}
+}
+
+func negateOperator(op string) string {
+	switch op {
+	case ">":
+		return "<="
+	case ">=":
+		return "<"
+	case "<":
+		return ">="
+	default:
+		return ">"
+	}
+}
+
+type likeMatch int
+
+const (
+	likeContains likeMatch = iota
+	likeBegins
+	likeEnds
+)
+
+func (m likeMatch) pattern(substr string, wildcard string) string {
+	switch m {
+	case likeBegins:
+		return substr + wildcard
+	case likeEnds:
+		return wildcard + substr
+	default:
+		return wildcard + substr + wildcard
+	}
+}
+
+func likeCondition(col string, substr string, match likeMatch, caseSensitive bool) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		name := queryDialectName(q)
+		if caseSensitive && name == dialect.SQLite {
+			// LIKE is always case-insensitive for ASCII characters in SQLite, GLOB is not.
+			return q.Where("?TableAlias.? GLOB ?", column(q, col), match.pattern(escapeGlob(substr), "*"))
+		}
+		return q.Where(
+			likeExpression(name, caseSensitive)+" ESCAPE ?",
+			column(q, col), match.pattern(escapeLike(name, substr), "%"), likeEscapeChar,
+		)
+	}
+}
+
+// CondLikeRaw is the Condition counterpart of WhereLikeRaw.
+func CondLikeRaw(col string, pattern string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where(likeExpression(queryDialectName(q), false), column(q, col), pattern)
+	}
+}
+
+const likeEscapeChar = `\`
+
+// escapeLike escapes LIKE wildcards, so the value is matched literally with ESCAPE likeEscapeChar.
+func escapeLike(name dialect.Name, value string) string {
+	special := `%_` + likeEscapeChar
+	if name == dialect.MSSQL {
+		// MSSQL also supports [] character ranges in LIKE patterns.
+		special += "["
+	}
+
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(special, r) {
+			b.WriteString(likeEscapeChar)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// escapeGlob escapes GLOB wildcards, GLOB has no ESCAPE clause, so they are wrapped into [] instead.
+func escapeGlob(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '*', '?', '[':
+			b.WriteRune('[')
+			b.WriteRune(r)
+			b.WriteRune(']')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// likeExpression returns the LIKE expression for the dialect, expecting column and pattern as arguments.
+func likeExpression(name dialect.Name, caseSensitive bool) string {
+	switch {
+	case name == dialect.PG && !caseSensitive:
+		return "?TableAlias.? ILIKE ?"
+	case name == dialect.MySQL && caseSensitive:
+		return "?TableAlias.? LIKE CAST(? AS BINARY)"
+	case name == dialect.MSSQL && caseSensitive:
+		return "?TableAlias.? COLLATE Latin1_General_CS_AS LIKE ?"
+	case !caseSensitive:
+		return "LOWER(?TableAlias.?) LIKE LOWER(?)"
+	default:
+		return "?TableAlias.? LIKE ?"
+	}
+}
+
+// setQueryErr sets the error on the query, so it is returned on execution.
+func setQueryErr(q bun.QueryBuilder, err error) bun.QueryBuilder {
+	switch query := q.Unwrap().(type) {
+	case *bun.SelectQuery:
+		query.Err(err)
+	case *bun.UpdateQuery:
+		query.Err(err)
+	case *bun.DeleteQuery:
+		query.Err(err)
+	}
+	return q
+}
+
+func queryDialectName(q bun.QueryBuilder) dialect.Name {
+	if query, ok := q.Unwrap().(interface{ Dialect() schema.Dialect }); ok {
+		return query.Dialect().Name()
+	}
+	return dialect.Invalid
+}
+
+func isZeroBound(value any) bool {
+	if value == nil {
+		return true
+	}
+	return reflect.ValueOf(value).IsZero()
+}
@@ -0,0 +1,136 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCondition_Select(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	result := query.Apply(CondEqual("name", "test").Select)
+	sql := result.String()
+
+	if !strings.Contains(sql, `WHERE ("test_model"."name" = 'test')`) {
+		t.Errorf("Condition.Select() should add condition, got %s", sql)
+	}
+}
+
+func TestCondition_Update(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewUpdate().Model((*testModel)(nil)).Set("name = ?", "new")
+	result := query.Apply(CondIn("id", []string{"1", "2"}).Update)
+	sql := result.String()
+
+	if !strings.HasPrefix(sql, "UPDATE") || !strings.Contains(sql, `WHERE ("test_model"."id" IN ('1', '2'))`) {
+		t.Errorf("Condition.Update() should add condition, got %s", sql)
+	}
+}
+
+func TestCondition_Delete(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewDelete().Model((*testModel)(nil))
+	result := query.Apply(CondOr(
+		CondEqual("name", "a"),
+		CondEqual("name", "b"),
+	).Delete)
+	sql := result.String()
+
+	if !strings.HasPrefix(sql, "DELETE") || !strings.Contains(sql, `WHERE ((("test_model"."name" = 'a')) OR (("test_model"."name" = 'b')))`) {
+		t.Errorf("Condition.Delete() should add OR group, got %s", sql)
+	}
+}
+
+func TestCondition_Nil(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	var cond Condition
+
+	if cond.Selector() != nil {
+		t.Error("Selector() should return nil for nil Condition")
+	}
+
+	sql := cond.Select(db.NewSelect().Model((*testModel)(nil))).String()
+	if strings.Contains(sql, "WHERE") {
+		t.Errorf("nil Condition should not add WHERE, got %s", sql)
+	}
+}
+
+func TestCondApplyIf(t *testing.T) {
+	if CondApplyIf(false, CondEqual("name", "test")) != nil {
+		t.Error("CondApplyIf() should return nil when condition is false")
+	}
+	if CondApplyIf(true, CondEqual("name", "test")) == nil {
+		t.Error("CondApplyIf() should return condition when condition is true")
+	}
+}
+
+func TestCondAndGroup(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewUpdate().Model((*testModel)(nil)).Set("name = ?", "new")
+	query = CondEqual("id", "1").Update(query)
+	result := CondAndGroup(
+		CondNotNull("name"),
+		CondBefore("created_at", time.Now()),
+	).Update(query)
+	sql := result.String()
+
+	if !strings.Contains(sql, `AND (("test_model"."name" is not null) AND ("test_model"."created_at" <= `) {
+		t.Errorf("CondAndGroup() should add AND group, got %s", sql)
+	}
+}
+
+func TestWhere_Condition(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	where := &Where{IDs: []string{"1", "2"}, HasFlags: []int{4}}
+
+	query := db.NewDelete().Model((*testModel)(nil))
+	result := query.Apply(where.Condition().Delete)
+	sql := result.String()
+
+	if !strings.Contains(sql, `"test_model"."id" IN ('1', '2')`) || !strings.Contains(sql, `"test_model"."flags" & 4 = 4`) {
+		t.Errorf("Where.Condition() should apply Where filters to DELETE, got %s", sql)
+	}
+
+	var nilWhere *Where
+	if nilWhere.Condition() != nil {
+		t.Error("Condition() should return nil for nil receiver")
+	}
+}
+
+func TestCondNot(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewDelete().Model((*testModel)(nil))
+	result := CondNot(CondIn("id", []string{"1", "2"})).Delete(query)
+	sql := result.String()
+
+	if !strings.Contains(sql, `WHERE NOT (("test_model"."id" IN ('1', '2')))`) {
+		t.Errorf("CondNot() should add NOT group, got %s", sql)
+	}
+
+	update := db.NewUpdate().Model((*testModel)(nil)).Set("name = ?", "x").Where("id = 1")
+	sql = CondNot(CondNull("name"), nil).Update(update).String()
+	if !strings.Contains(sql, `WHERE (id = 1) AND NOT (("test_model"."name" is null))`) {
+		t.Errorf("CondNot() should add NOT group after other conditions, got %s", sql)
+	}
+
+	update = db.NewUpdate().Model((*testModel)(nil)).Set("name = ?", "x").Where("id = 1")
+	sql = CondNot(CondGt("id", 0), CondAndGroup(CondBetween("id", nil, 0))).Update(update).String()
+	if !strings.HasSuffix(sql, `WHERE (id = 1)`) {
+		t.Errorf("CondNot() should add nothing for skipped conditions, got %s", sql)
+	}
+}
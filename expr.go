@@ -0,0 +1,629 @@
+package bunutils
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ExprOp is an operator of Expr, named as in the canonical string form.
+type ExprOp string
+
+// Group operators, their conditions are Expr.Nodes.
+const (
+	ExprOpAnd        ExprOp = "and"
+	ExprOpOr         ExprOp = "or"
+	ExprOpNot        ExprOp = "not"
+	ExprOpHas        ExprOp = "has"
+	ExprOpDoesntHave ExprOp = "doesnt_have"
+)
+
+// Column operators, their values are Expr.Args in the order of the Where* arguments after the column.
+const (
+	ExprOpEqual                              ExprOp = "eq"
+	ExprOpNotEqual                           ExprOp = "ne"
+	ExprOpGt                                 ExprOp = "gt"
+	ExprOpGte                                ExprOp = "gte"
+	ExprOpLt                                 ExprOp = "lt"
+	ExprOpLte                                ExprOp = "lte"
+	ExprOpIn                                 ExprOp = "in"
+	ExprOpNotIn                              ExprOp = "nin"
+	ExprOpNull                               ExprOp = "null"
+	ExprOpNotNull                            ExprOp = "not_null"
+	ExprOpBefore                             ExprOp = "before"
+	ExprOpAfter                              ExprOp = "after"
+	ExprOpBetween                            ExprOp = "between"
+	ExprOpNotBetween                         ExprOp = "not_between"
+	ExprOpRange                              ExprOp = "range"
+	ExprOpNotRange                           ExprOp = "not_range"
+	ExprOpContains                           ExprOp = "contains"
+	ExprOpBegins                             ExprOp = "begins"
+	ExprOpEnds                               ExprOp = "ends"
+	ExprOpContainsCaseSensitive              ExprOp = "contains_cs"
+	ExprOpBeginsCaseSensitive                ExprOp = "begins_cs"
+	ExprOpEndsCaseSensitive                  ExprOp = "ends_cs"
+	ExprOpLikeRaw                            ExprOp = "like"
+	ExprOpAnyEqual                           ExprOp = "any_eq"
+	ExprOpArrayContains                      ExprOp = "array_contains"
+	ExprOpArrayContainedBy                   ExprOp = "array_contained_by"
+	ExprOpArrayOverlaps                      ExprOp = "array_overlaps"
+	ExprOpArrayLength                        ExprOp = "array_length"
+	ExprOpJsonbEqual                         ExprOp = "jsonb_eq"
+	ExprOpJsonbContains                      ExprOp = "jsonb_contains"
+	ExprOpJsonbHasKey                        ExprOp = "jsonb_has_key"
+	ExprOpJsonbHasAnyKeys                    ExprOp = "jsonb_has_any_keys"
+	ExprOpJsonbHasAllKeys                    ExprOp = "jsonb_has_all_keys"
+	ExprOpJsonbPathEqual                     ExprOp = "jsonb_path_eq"
+	ExprOpJsonbPathNumber                    ExprOp = "jsonb_path_number"
+	ExprOpJsonbPathBool                      ExprOp = "jsonb_path_bool"
+	ExprOpJsonbPathTime                      ExprOp = "jsonb_path_time"
+	ExprOpJsonbPathExists                    ExprOp = "jsonb_path_exists"
+	ExprOpJsonbPathMatch                     ExprOp = "jsonb_path_match"
+	ExprOpJsonbObjectsArrayKeyValueEqual     ExprOp = "jsonb_objects_array_eq"
+	ExprOpJsonbPathObjectsArrayKeyValueEqual ExprOp = "jsonb_path_objects_array_eq"
+	ExprOpSimilar                            ExprOp = "similar"
+	ExprOpTrigramMatch                       ExprOp = "trigram_match"
+)
+
+// exprArgs is the number of Expr.Args of the operators, -1 for group operators.
+var exprArgs = map[ExprOp]int{
+	ExprOpAnd: -1, ExprOpOr: -1, ExprOpNot: -1, ExprOpHas: -1, ExprOpDoesntHave: -1,
+
+	ExprOpEqual: 1, ExprOpNotEqual: 1, ExprOpGt: 1, ExprOpGte: 1, ExprOpLt: 1, ExprOpLte: 1,
+	ExprOpIn: 1, ExprOpNotIn: 1, ExprOpNull: 0, ExprOpNotNull: 0, ExprOpBefore: 1, ExprOpAfter: 1,
+	ExprOpBetween: 2, ExprOpNotBetween: 2, ExprOpRange: 3, ExprOpNotRange: 3,
+	ExprOpContains: 1, ExprOpBegins: 1, ExprOpEnds: 1,
+	ExprOpContainsCaseSensitive: 1, ExprOpBeginsCaseSensitive: 1, ExprOpEndsCaseSensitive: 1, ExprOpLikeRaw: 1,
+	ExprOpAnyEqual: 1, ExprOpArrayContains: 1, ExprOpArrayContainedBy: 1, ExprOpArrayOverlaps: 1, ExprOpArrayLength: 2,
+	ExprOpJsonbEqual: 2, ExprOpJsonbContains: 1, ExprOpJsonbHasKey: 1, ExprOpJsonbHasAnyKeys: 1, ExprOpJsonbHasAllKeys: 1,
+	ExprOpJsonbPathEqual: 1, ExprOpJsonbPathNumber: 2, ExprOpJsonbPathBool: 1, ExprOpJsonbPathTime: 2,
+	ExprOpJsonbPathExists: 2, ExprOpJsonbPathMatch: 2,
+	ExprOpJsonbObjectsArrayKeyValueEqual: 3, ExprOpJsonbPathObjectsArrayKeyValueEqual: 2,
+	ExprOpSimilar: 2, ExprOpTrigramMatch: 1,
+}
+
+// Expr is an inspectable condition: a column comparison or an and, or, not, has, doesnt_have group.
+// Unlike Selector and Condition it can be walked, logged and compared by its canonical String:
+//
+//	expr := bunutils.ExprAnd(
+//	    bunutils.ExprEqual("tenant_id", tenantID),
+//	    bunutils.ExprOr(bunutils.ExprGte("age", 18), bunutils.ExprIn("role", []string{"admin", "owner"})),
+//	)
+//	db.NewSelect().Model(&users).Apply(expr.Select)
+//
+// The Expr* constructors are the counterparts of the Where* ones, except WhereFullText and WhereDistinctOn.
+type Expr struct {
+	Op ExprOp
+	// Field is the column, or the relation of has and doesnt_have.
+	Field string
+	// Path is the JSONB path of jsonb operators.
+	Path []string
+	// Args are the values of column operators.
+	Args []any
+	// Nodes are the conditions of group operators.
+	Nodes []Expr
+}
+
+// Condition renders the Expr into a Condition.
+// Invalid operators and arguments are reported as a query error.
+func (e Expr) Condition() Condition {
+	cond, err := e.condition()
+	if err != nil {
+		return func(q bun.QueryBuilder) bun.QueryBuilder {
+			return setQueryErr(q, err)
+		}
+	}
+	return cond
+}
+
+// Select applies the Expr to a SELECT query. The method value e.Select can be used as a Selector.
+func (e Expr) Select(q *bun.SelectQuery) *bun.SelectQuery {
+	return e.Condition().Select(q)
+}
+
+// Update applies the Expr to an UPDATE query.
+func (e Expr) Update(q *bun.UpdateQuery) *bun.UpdateQuery {
+	return e.Condition().Update(q)
+}
+
+// Delete applies the Expr to a DELETE query.
+func (e Expr) Delete(q *bun.DeleteQuery) *bun.DeleteQuery {
+	return e.Condition().Delete(q)
+}
+
+// Walk calls visit for the Expr and its nodes in depth-first order.
+// Nodes of a group are skipped if visit returns false for it. Nodes of has and doesnt_have
+// are conditions of the related table, not of the query model:
+//
+//	hasTenant := false
+//	expr.Walk(func(e bunutils.Expr) bool {
+//	    hasTenant = hasTenant || e.Op == bunutils.ExprOpEqual && e.Field == "tenant_id"
+//	    return e.Op == bunutils.ExprOpAnd // only top-level AND conditions restrict all rows
+//	})
+func (e Expr) Walk(visit func(Expr) bool) {
+	if !visit(e) {
+		return
+	}
+	for _, node := range e.Nodes {
+		node.Walk(visit)
+	}
+}
+
+// String returns the canonical form of the Expr, e.g. and(eq(status, "active"), gte(age, 18)).
+// Nodes of groups are sorted, so equivalent Exprs built in a different order have the same String,
+// which can be logged or used as a cache key.
+func (e Expr) String() string {
+	var b strings.Builder
+	e.writeString(&b)
+	return b.String()
+}
+
+func (e Expr) writeString(b *strings.Builder) {
+	b.WriteString(string(e.Op))
+	b.WriteByte('(')
+
+	var parts []string
+	if e.Field != "" {
+		parts = append(parts, e.Field)
+	}
+	if e.Path != nil {
+		parts = append(parts, formatExprValue(e.Path))
+	}
+	for _, arg := range e.Args {
+		parts = append(parts, formatExprValue(arg))
+	}
+
+	nodes := make([]string, len(e.Nodes))
+	for i, node := range e.Nodes {
+		nodes[i] = node.String()
+	}
+	slices.Sort(nodes)
+
+	b.WriteString(strings.Join(append(parts, nodes...), ", "))
+	b.WriteByte(')')
+}
+
+func formatExprValue(value any) string {
+	if value == nil {
+		return "null"
+	}
+	// Pointers are dereferenced first, methods of fmt.Stringer values can't be called on nil pointers.
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "null"
+		}
+		return formatExprValue(v.Elem().Interface())
+	}
+
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case []byte:
+		return strconv.Quote(string(value))
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatExprValue(v.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		keys := v.MapKeys()
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = formatExprValue(key.Interface()) + ": " + formatExprValue(v.MapIndex(key).Interface())
+		}
+		slices.Sort(items)
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func (e Expr) condition() (Condition, error) {
+	n, ok := exprArgs[e.Op]
+	if !ok {
+		return nil, fmt.Errorf("bunutils: unknown expression operator %q", e.Op)
+	}
+	if n < 0 {
+		return e.groupCondition()
+	}
+	if len(e.Args) != n {
+		return nil, fmt.Errorf("bunutils: expression %s expects %d arguments, got %d", e.Op, n, len(e.Args))
+	}
+
+	var err error
+	col := e.Field
+	var cond Condition
+	switch e.Op {
+	case ExprOpEqual:
+		cond = CondEqual(col, e.Args[0])
+	case ExprOpNotEqual:
+		cond = CondNotEqual(col, e.Args[0])
+	case ExprOpGt:
+		cond = CondGt(col, e.Args[0])
+	case ExprOpGte:
+		cond = CondGte(col, e.Args[0])
+	case ExprOpLt:
+		cond = CondLt(col, e.Args[0])
+	case ExprOpLte:
+		cond = CondLte(col, e.Args[0])
+	case ExprOpIn:
+		cond = CondIn(col, e.Args[0])
+	case ExprOpNotIn:
+		cond = CondNotIn(col, e.Args[0])
+	case ExprOpNull:
+		cond = CondNull(col)
+	case ExprOpNotNull:
+		cond = CondNotNull(col)
+	case ExprOpBefore:
+		cond = CondBefore(col, exprArg[time.Time](e, 0, &err))
+	case ExprOpAfter:
+		cond = CondAfter(col, exprArg[time.Time](e, 0, &err))
+	case ExprOpBetween:
+		cond = CondBetween(col, e.Args[0], e.Args[1])
+	case ExprOpNotBetween:
+		cond = CondNotBetween(col, e.Args[0], e.Args[1])
+	case ExprOpRange:
+		cond = CondRange(col, e.Args[0], e.Args[1], exprArg[Bounds](e, 2, &err))
+	case ExprOpNotRange:
+		cond = CondNotRange(col, e.Args[0], e.Args[1], exprArg[Bounds](e, 2, &err))
+	case ExprOpContains:
+		cond = CondContains(col, exprArg[string](e, 0, &err))
+	case ExprOpBegins:
+		cond = CondBegins(col, exprArg[string](e, 0, &err))
+	case ExprOpEnds:
+		cond = CondEnds(col, exprArg[string](e, 0, &err))
+	case ExprOpContainsCaseSensitive:
+		cond = CondContainsCaseSensitive(col, exprArg[string](e, 0, &err))
+	case ExprOpBeginsCaseSensitive:
+		cond = CondBeginsCaseSensitive(col, exprArg[string](e, 0, &err))
+	case ExprOpEndsCaseSensitive:
+		cond = CondEndsCaseSensitive(col, exprArg[string](e, 0, &err))
+	case ExprOpLikeRaw:
+		cond = CondLikeRaw(col, exprArg[string](e, 0, &err))
+	case ExprOpAnyEqual:
+		cond = CondAnyEqual(col, e.Args[0])
+	case ExprOpArrayContains:
+		cond = CondArrayContains(col, e.Args[0])
+	case ExprOpArrayContainedBy:
+		cond = CondArrayContainedBy(col, e.Args[0])
+	case ExprOpArrayOverlaps:
+		cond = CondArrayOverlaps(col, e.Args[0])
+	case ExprOpArrayLength:
+		cond = CondArrayLength(col, exprArg[CompareOp](e, 0, &err), exprArg[int](e, 1, &err))
+	case ExprOpJsonbEqual:
+		cond = CondJsonbEqual(col, exprArg[string](e, 0, &err), e.Args[1])
+	case ExprOpJsonbContains:
+		cond = CondJsonbContains(col, e.Path, e.Args[0])
+	case ExprOpJsonbHasKey:
+		cond = CondJsonbHasKey(col, e.Path, exprArg[string](e, 0, &err))
+	case ExprOpJsonbHasAnyKeys:
+		cond = CondJsonbHasAnyKeys(col, e.Path, exprArg[[]string](e, 0, &err)...)
+	case ExprOpJsonbHasAllKeys:
+		cond = CondJsonbHasAllKeys(col, e.Path, exprArg[[]string](e, 0, &err)...)
+	case ExprOpJsonbPathEqual:
+		cond = CondJsonbPathEqual(col, e.Path, e.Args[0])
+	case ExprOpJsonbPathNumber:
+		cond = CondJsonbPathNumber(col, e.Path, exprArg[CompareOp](e, 0, &err), e.Args[1])
+	case ExprOpJsonbPathBool:
+		cond = CondJsonbPathBool(col, e.Path, exprArg[bool](e, 0, &err))
+	case ExprOpJsonbPathTime:
+		cond = CondJsonbPathTime(col, e.Path, exprArg[CompareOp](e, 0, &err), exprArg[time.Time](e, 1, &err))
+	case ExprOpJsonbPathExists:
+		cond = CondJsonbPathExists(col, exprArg[string](e, 0, &err), exprArg[map[string]any](e, 1, &err))
+	case ExprOpJsonbPathMatch:
+		cond = CondJsonbPathMatch(col, exprArg[string](e, 0, &err), exprArg[map[string]any](e, 1, &err))
+	case ExprOpJsonbObjectsArrayKeyValueEqual:
+		cond = CondJsonbObjectsArrayKeyValueEqual(col, exprArg[string](e, 0, &err), exprArg[string](e, 1, &err), e.Args[2])
+	case ExprOpJsonbPathObjectsArrayKeyValueEqual:
+		cond = CondJsonbPathObjectsArrayKeyValueEqual(col, e.Path, exprArg[string](e, 0, &err), e.Args[1])
+	case ExprOpSimilar:
+		cond = CondSimilar(col, exprArg[string](e, 0, &err), exprArg[float64](e, 1, &err))
+	case ExprOpTrigramMatch:
+		cond = CondTrigramMatch(col, exprArg[string](e, 0, &err))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cond, nil
+}
+
+func (e Expr) groupCondition() (Condition, error) {
+	conds := make([]Condition, len(e.Nodes))
+	for i, node := range e.Nodes {
+		cond, err := node.condition()
+		if err != nil {
+			return nil, err
+		}
+		conds[i] = cond
+	}
+
+	switch e.Op {
+	case ExprOpOr:
+		return CondOr(conds...), nil
+	case ExprOpNot:
+		return CondNot(conds...), nil
+	case ExprOpHas, ExprOpDoesntHave:
+		selectors := Map(conds, func(c Condition, _ int) Selector {
+			return c.Selector()
+		})
+		if e.Op == ExprOpHas {
+			return CondHas(e.Field, selectors...), nil
+		}
+		return CondDoesntHave(e.Field, selectors...), nil
+	default:
+		return CondAndGroup(conds...), nil
+	}
+}
+
+// exprArg returns the argument of the type T, nil is the zero value. The first mismatch is stored in err.
+func exprArg[T any](e Expr, i int, err *error) T {
+	v, ok := e.Args[i].(T)
+	if !ok && e.Args[i] != nil && *err == nil {
+		*err = fmt.Errorf("bunutils: expression %s argument %d is %T, expected %T", e.Op, i+1, e.Args[i], v)
+	}
+	return v
+}
+
+// ExprAnd is the Expr counterpart of AndGroup.
+func ExprAnd(exprs ...Expr) Expr {
+	return Expr{Op: ExprOpAnd, Nodes: exprs}
+}
+
+// ExprOr is the Expr counterpart of Or.
+func ExprOr(exprs ...Expr) Expr {
+	return Expr{Op: ExprOpOr, Nodes: exprs}
+}
+
+// ExprNot is the Expr counterpart of Not.
+func ExprNot(exprs ...Expr) Expr {
+	return Expr{Op: ExprOpNot, Nodes: exprs}
+}
+
+// ExprHas is the Expr counterpart of WhereHas.
+func ExprHas(relation string, exprs ...Expr) Expr {
+	return Expr{Op: ExprOpHas, Field: relation, Nodes: exprs}
+}
+
+// ExprDoesntHave is the Expr counterpart of WhereDoesntHave.
+func ExprDoesntHave(relation string, exprs ...Expr) Expr {
+	return Expr{Op: ExprOpDoesntHave, Field: relation, Nodes: exprs}
+}
+
+func columnExpr(op ExprOp, col string, args ...any) Expr {
+	return Expr{Op: op, Field: col, Args: args}
+}
+
+func jsonbExpr(op ExprOp, col string, path []string, args ...any) Expr {
+	return Expr{Op: op, Field: col, Path: path, Args: args}
+}
+
+// ExprEqual is the Expr counterpart of WhereEqual.
+func ExprEqual(col string, value any) Expr {
+	return columnExpr(ExprOpEqual, col, value)
+}
+
+// ExprNotEqual is the Expr counterpart of WhereNotEqual.
+func ExprNotEqual(col string, value any) Expr {
+	return columnExpr(ExprOpNotEqual, col, value)
+}
+
+// ExprGt is the Expr counterpart of WhereGt.
+func ExprGt(col string, value any) Expr {
+	return columnExpr(ExprOpGt, col, value)
+}
+
+// ExprGte is the Expr counterpart of WhereGte.
+func ExprGte(col string, value any) Expr {
+	return columnExpr(ExprOpGte, col, value)
+}
+
+// ExprLt is the Expr counterpart of WhereLt.
+func ExprLt(col string, value any) Expr {
+	return columnExpr(ExprOpLt, col, value)
+}
+
+// ExprLte is the Expr counterpart of WhereLte.
+func ExprLte(col string, value any) Expr {
+	return columnExpr(ExprOpLte, col, value)
+}
+
+// ExprIn is the Expr counterpart of WhereIn.
+func ExprIn(col string, values any) Expr {
+	return columnExpr(ExprOpIn, col, values)
+}
+
+// ExprNotIn is the Expr counterpart of WhereNotIn.
+func ExprNotIn(col string, values any) Expr {
+	return columnExpr(ExprOpNotIn, col, values)
+}
+
+// ExprNull is the Expr counterpart of WhereNull.
+func ExprNull(col string) Expr {
+	return columnExpr(ExprOpNull, col)
+}
+
+// ExprNotNull is the Expr counterpart of WhereNotNull.
+func ExprNotNull(col string) Expr {
+	return columnExpr(ExprOpNotNull, col)
+}
+
+// ExprBefore is the Expr counterpart of WhereBefore.
+func ExprBefore(col string, t time.Time) Expr {
+	return columnExpr(ExprOpBefore, col, t)
+}
+
+// ExprAfter is the Expr counterpart of WhereAfter.
+func ExprAfter(col string, t time.Time) Expr {
+	return columnExpr(ExprOpAfter, col, t)
+}
+
+// ExprBetween is the Expr counterpart of WhereBetween.
+func ExprBetween(col string, from, to any) Expr {
+	return columnExpr(ExprOpBetween, col, from, to)
+}
+
+// ExprNotBetween is the Expr counterpart of WhereNotBetween.
+func ExprNotBetween(col string, from, to any) Expr {
+	return columnExpr(ExprOpNotBetween, col, from, to)
+}
+
+// ExprRange is the Expr counterpart of WhereRange.
+func ExprRange(col string, from, to any, bounds Bounds) Expr {
+	return columnExpr(ExprOpRange, col, from, to, bounds)
+}
+
+// ExprNotRange is the Expr counterpart of WhereNotRange.
+func ExprNotRange(col string, from, to any, bounds Bounds) Expr {
+	return columnExpr(ExprOpNotRange, col, from, to, bounds)
+}
+
+// ExprContains is the Expr counterpart of WhereContains.
+func ExprContains(col string, substr string) Expr {
+	return columnExpr(ExprOpContains, col, substr)
+}
+
+// ExprBegins is the Expr counterpart of WhereBegins.
+func ExprBegins(col string, substr string) Expr {
+	return columnExpr(ExprOpBegins, col, substr)
+}
+
+// ExprEnds is the Expr counterpart of WhereEnds.
+func ExprEnds(col string, substr string) Expr {
+	return columnExpr(ExprOpEnds, col, substr)
+}
+
+// ExprContainsCaseSensitive is the Expr counterpart of WhereContainsCaseSensitive.
+func ExprContainsCaseSensitive(col string, substr string) Expr {
+	return columnExpr(ExprOpContainsCaseSensitive, col, substr)
+}
+
+// ExprBeginsCaseSensitive is the Expr counterpart of WhereBeginsCaseSensitive.
+func ExprBeginsCaseSensitive(col string, substr string) Expr {
+	return columnExpr(ExprOpBeginsCaseSensitive, col, substr)
+}
+
+// ExprEndsCaseSensitive is the Expr counterpart of WhereEndsCaseSensitive.
+func ExprEndsCaseSensitive(col string, substr string) Expr {
+	return columnExpr(ExprOpEndsCaseSensitive, col, substr)
+}
+
+// ExprLikeRaw is the Expr counterpart of WhereLikeRaw.
+func ExprLikeRaw(col string, pattern string) Expr {
+	return columnExpr(ExprOpLikeRaw, col, pattern)
+}
+
+// ExprAnyEqual is the Expr counterpart of WhereAnyEqual.
+func ExprAnyEqual(col string, value any) Expr {
+	return columnExpr(ExprOpAnyEqual, col, value)
+}
+
+// ExprArrayContains is the Expr counterpart of WhereArrayContains.
+func ExprArrayContains(col string, values any) Expr {
+	return columnExpr(ExprOpArrayContains, col, values)
+}
+
+// ExprArrayContainedBy is the Expr counterpart of WhereArrayContainedBy.
+func ExprArrayContainedBy(col string, values any) Expr {
+	return columnExpr(ExprOpArrayContainedBy, col, values)
+}
+
+// ExprArrayOverlaps is the Expr counterpart of WhereArrayOverlaps.
+func ExprArrayOverlaps(col string, values any) Expr {
+	return columnExpr(ExprOpArrayOverlaps, col, values)
+}
+
+// ExprArrayLength is the Expr counterpart of WhereArrayLength.
+func ExprArrayLength(col string, op CompareOp, length int) Expr {
+	return columnExpr(ExprOpArrayLength, col, op, length)
+}
+
+// ExprJsonbEqual is the Expr counterpart of WhereJsonbEqual.
+func ExprJsonbEqual(col string, field string, value any) Expr {
+	return columnExpr(ExprOpJsonbEqual, col, field, value)
+}
+
+// ExprJsonbContains is the Expr counterpart of WhereJsonbContains.
+func ExprJsonbContains(col string, path []string, value any) Expr {
+	return jsonbExpr(ExprOpJsonbContains, col, path, value)
+}
+
+// ExprJsonbHasKey is the Expr counterpart of WhereJsonbHasKey.
+func ExprJsonbHasKey(col string, path []string, key string) Expr {
+	return jsonbExpr(ExprOpJsonbHasKey, col, path, key)
+}
+
+// ExprJsonbHasAnyKeys is the Expr counterpart of WhereJsonbHasAnyKeys.
+func ExprJsonbHasAnyKeys(col string, path []string, keys ...string) Expr {
+	return jsonbExpr(ExprOpJsonbHasAnyKeys, col, path, keys)
+}
+
+// ExprJsonbHasAllKeys is the Expr counterpart of WhereJsonbHasAllKeys.
+func ExprJsonbHasAllKeys(col string, path []string, keys ...string) Expr {
+	return jsonbExpr(ExprOpJsonbHasAllKeys, col, path, keys)
+}
+
+// ExprJsonbPathEqual is the Expr counterpart of WhereJsonbPathEqual.
+func ExprJsonbPathEqual(col string, path []string, value any) Expr {
+	return jsonbExpr(ExprOpJsonbPathEqual, col, path, value)
+}
+
+// ExprJsonbPathNumber is the Expr counterpart of WhereJsonbPathNumber.
+func ExprJsonbPathNumber(col string, path []string, op CompareOp, value any) Expr {
+	return jsonbExpr(ExprOpJsonbPathNumber, col, path, op, value)
+}
+
+// ExprJsonbPathBool is the Expr counterpart of WhereJsonbPathBool.
+func ExprJsonbPathBool(col string, path []string, value bool) Expr {
+	return jsonbExpr(ExprOpJsonbPathBool, col, path, value)
+}
+
+// ExprJsonbPathTime is the Expr counterpart of WhereJsonbPathTime.
+func ExprJsonbPathTime(col string, path []string, op CompareOp, t time.Time) Expr {
+	return jsonbExpr(ExprOpJsonbPathTime, col, path, op, t)
+}
+
+// ExprJsonbPathExists is the Expr counterpart of WhereJsonbPathExists.
+func ExprJsonbPathExists(col string, jsonpath string, vars map[string]any) Expr {
+	return columnExpr(ExprOpJsonbPathExists, col, jsonpath, vars)
+}
+
+// ExprJsonbPathMatch is the Expr counterpart of WhereJsonbPathMatch.
+func ExprJsonbPathMatch(col string, jsonpath string, vars map[string]any) Expr {
+	return columnExpr(ExprOpJsonbPathMatch, col, jsonpath, vars)
+}
+
+// ExprJsonbObjectsArrayKeyValueEqual is the Expr counterpart of WhereJsonbObjectsArrayKeyValueEqual.
+func ExprJsonbObjectsArrayKeyValueEqual(col string, key, field string, value any) Expr {
+	return columnExpr(ExprOpJsonbObjectsArrayKeyValueEqual, col, key, field, value)
+}
+
+// ExprJsonbPathObjectsArrayKeyValueEqual is the Expr counterpart of WhereJsonbPathObjectsArrayKeyValueEqual.
+func ExprJsonbPathObjectsArrayKeyValueEqual(col string, path []string, field string, value any) Expr {
+	return jsonbExpr(ExprOpJsonbPathObjectsArrayKeyValueEqual, col, path, field, value)
+}
+
+// ExprSimilar is the Expr counterpart of WhereSimilar.
+func ExprSimilar(col string, text string, threshold float64) Expr {
+	return columnExpr(ExprOpSimilar, col, text, threshold)
+}
+
+// ExprTrigramMatch is the Expr counterpart of WhereTrigramMatch.
+func ExprTrigramMatch(col string, text string) Expr {
+	return columnExpr(ExprOpTrigramMatch, col, text)
+}
@@ -0,0 +1,169 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpr_Select(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		expr     Expr
+		selector Selector
+	}{
+		{"equal", ExprEqual("status", "active"), WhereEqual("status", "active")},
+		{"not equal", ExprNotEqual("status", "active"), WhereNotEqual("status", "active")},
+		{"gt", ExprGt("age", 18), WhereGt("age", 18)},
+		{"gte zero", ExprGte("age", ToPtr(0)), WhereGte("age", ToPtr(0))},
+		{"lt", ExprLt("age", 18), WhereLt("age", 18)},
+		{"lte", ExprLte("age", 18), WhereLte("age", 18)},
+		{"in", ExprIn("id", []int{1, 2}), WhereIn("id", []int{1, 2})},
+		{"not in", ExprNotIn("id", []int{1, 2}), WhereNotIn("id", []int{1, 2})},
+		{"null", ExprNull("deleted_at"), WhereNull("deleted_at")},
+		{"not null", ExprNotNull("deleted_at"), WhereNotNull("deleted_at")},
+		{"before", ExprBefore("created_at", ts), WhereBefore("created_at", ts)},
+		{"after", ExprAfter("created_at", ts), WhereAfter("created_at", ts)},
+		{"between", ExprBetween("age", 18, 65), WhereBetween("age", 18, 65)},
+		{"not between", ExprNotBetween("age", 18, 65), WhereNotBetween("age", 18, 65)},
+		{"range", ExprRange("age", 18, 65, BoundsLowerInclusive), WhereRange("age", 18, 65, BoundsLowerInclusive)},
+		{"not range", ExprNotRange("age", 18, 65, BoundsExclusive), WhereNotRange("age", 18, 65, BoundsExclusive)},
+		{"contains", ExprContains("name", "jo"), WhereContains("name", "jo")},
+		{"begins", ExprBegins("name", "jo"), WhereBegins("name", "jo")},
+		{"ends", ExprEnds("name", "jo"), WhereEnds("name", "jo")},
+		{"contains case sensitive", ExprContainsCaseSensitive("name", "Jo"), WhereContainsCaseSensitive("name", "Jo")},
+		{"begins case sensitive", ExprBeginsCaseSensitive("name", "Jo"), WhereBeginsCaseSensitive("name", "Jo")},
+		{"ends case sensitive", ExprEndsCaseSensitive("name", "Jo"), WhereEndsCaseSensitive("name", "Jo")},
+		{"like raw", ExprLikeRaw("name", "j_%"), WhereLikeRaw("name", "j_%")},
+		{"any equal", ExprAnyEqual("tags", "go"), WhereAnyEqual("tags", "go")},
+		{"array contains", ExprArrayContains("tags", []string{"go"}), WhereArrayContains("tags", []string{"go"})},
+		{"array contained by", ExprArrayContainedBy("tags", []string{"go"}), WhereArrayContainedBy("tags", []string{"go"})},
+		{"array overlaps", ExprArrayOverlaps("tags", []string{"go"}), WhereArrayOverlaps("tags", []string{"go"})},
+		{"array length", ExprArrayLength("tags", OpGreater, 2), WhereArrayLength("tags", OpGreater, 2)},
+		{"jsonb equal", ExprJsonbEqual("data", "kind", "a"), WhereJsonbEqual("data", "kind", "a")},
+		{"jsonb contains", ExprJsonbContains("data", []string{"a"}, map[string]int{"b": 1}), WhereJsonbContains("data", []string{"a"}, map[string]int{"b": 1})},
+		{"jsonb has key", ExprJsonbHasKey("data", []string{"a"}, "b"), WhereJsonbHasKey("data", []string{"a"}, "b")},
+		{"jsonb has any keys", ExprJsonbHasAnyKeys("data", nil, "a", "b"), WhereJsonbHasAnyKeys("data", nil, "a", "b")},
+		{"jsonb has all keys", ExprJsonbHasAllKeys("data", nil, "a", "b"), WhereJsonbHasAllKeys("data", nil, "a", "b")},
+		{"jsonb path equal", ExprJsonbPathEqual("data", []string{"a", "b"}, 1), WhereJsonbPathEqual("data", []string{"a", "b"}, 1)},
+		{"jsonb path number", ExprJsonbPathNumber("data", []string{"n"}, OpLess, 5), WhereJsonbPathNumber("data", []string{"n"}, OpLess, 5)},
+		{"jsonb path bool", ExprJsonbPathBool("data", []string{"ok"}, true), WhereJsonbPathBool("data", []string{"ok"}, true)},
+		{"jsonb path time", ExprJsonbPathTime("data", []string{"at"}, OpGreater, ts), WhereJsonbPathTime("data", []string{"at"}, OpGreater, ts)},
+		{"jsonb path exists", ExprJsonbPathExists("data", "$.a", nil), WhereJsonbPathExists("data", "$.a", nil)},
+		{"jsonb path match", ExprJsonbPathMatch("data", "$.n > $x", map[string]any{"x": 1}), WhereJsonbPathMatch("data", "$.n > $x", map[string]any{"x": 1})},
+		{"jsonb objects array", ExprJsonbObjectsArrayKeyValueEqual("data", "items", "id", 1), WhereJsonbObjectsArrayKeyValueEqual("data", "items", "id", 1)},
+		{"jsonb path objects array", ExprJsonbPathObjectsArrayKeyValueEqual("data", []string{"a"}, "id", 1), WhereJsonbPathObjectsArrayKeyValueEqual("data", []string{"a"}, "id", 1)},
+		{"similar", ExprSimilar("name", "jon", 0.3), WhereSimilar("name", "jon", 0.3)},
+		{"trigram match", ExprTrigramMatch("name", "jon"), WhereTrigramMatch("name", "jon")},
+		{
+			"groups",
+			ExprAnd(ExprEqual("a", 1), ExprOr(ExprEqual("b", 2), ExprNot(ExprEqual("c", 3)))),
+			AndGroup(WhereEqual("a", 1), Or(WhereEqual("b", 2), Not(WhereEqual("c", 3)))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.NewSelect().Model((*testModel)(nil)).Apply(tt.expr.Select).String()
+			want := db.NewSelect().Model((*testModel)(nil)).Apply(tt.selector).String()
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestExpr_Has(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+	db.RegisterModel((*relUserGroup)(nil))
+
+	got := db.NewSelect().Model((*relUser)(nil)).Apply(ExprHas("Orders", ExprEqual("status", "paid")).Select).String()
+	want := db.NewSelect().Model((*relUser)(nil)).Apply(WhereHas("Orders", WhereEqual("status", "paid"))).String()
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestExpr_String(t *testing.T) {
+	a := ExprAnd(
+		ExprEqual("tenant_id", 7),
+		ExprOr(ExprIn("role", []string{"admin", "owner"}), ExprGte("age", ToPtr(18))),
+		ExprJsonbPathExists("data", "$.a", map[string]any{"b": 1, "a": "x"}),
+	)
+	b := ExprAnd(
+		ExprJsonbPathExists("data", "$.a", map[string]any{"a": "x", "b": 1}),
+		ExprOr(ExprGte("age", 18), ExprIn("role", []string{"admin", "owner"})),
+		ExprEqual("tenant_id", 7),
+	)
+
+	want := `and(eq(tenant_id, 7), jsonb_path_exists(data, "$.a", {"a": "x", "b": 1}), or(gte(age, 18), in(role, ["admin", "owner"])))`
+	if a.String() != want {
+		t.Errorf("String() = %s, want %s", a, want)
+	}
+	if a.String() != b.String() {
+		t.Errorf("String() of equivalent expressions differ: %s, %s", a, b)
+	}
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))
+	if got := ExprAfter("created_at", ts).String(); got != "after(created_at, 2025-01-02T02:04:05Z)" {
+		t.Errorf("String() = %s", got)
+	}
+	if got := ExprJsonbHasKey("data", []string{"a", "b"}, "c").String(); got != `jsonb_has_key(data, ["a", "b"], "c")` {
+		t.Errorf("String() = %s", got)
+	}
+	if got := ExprAnd(ExprGte("created_at", (*time.Time)(nil)), ExprEqual("a", 1)).String(); got != "and(eq(a, 1), gte(created_at, null))" {
+		t.Errorf("String() = %s", got)
+	}
+	if got := ExprGt("created_at", &ts).String(); got != "gt(created_at, 2025-01-02T02:04:05Z)" {
+		t.Errorf("String() of *time.Time = %s", got)
+	}
+}
+
+func TestExpr_Walk(t *testing.T) {
+	expr := ExprAnd(
+		ExprEqual("status", "active"),
+		ExprOr(ExprEqual("tenant_id", 1), ExprNull("deleted_at")),
+		ExprNot(ExprEqual("tenant_id", 2)),
+	)
+
+	var fields []string
+	expr.Walk(func(e Expr) bool {
+		if e.Field != "" {
+			fields = append(fields, e.Field)
+		}
+		return e.Op != ExprOpNot
+	})
+	if got := strings.Join(fields, ","); got != "status,tenant_id,deleted_at" {
+		t.Errorf("Walk() visited %s", got)
+	}
+}
+
+func TestExpr_Errors(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name string
+		expr Expr
+		want string
+	}{
+		{"unknown operator", Expr{Op: "regex", Field: "name"}, `unknown expression operator "regex"`},
+		{"arguments", Expr{Op: ExprOpEqual, Field: "name"}, "expression eq expects 1 arguments, got 0"},
+		{"argument type", ExprAnd(Expr{Op: ExprOpContains, Field: "name", Args: []any{1}}), "expression contains argument 1 is int, expected string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := db.NewSelect().Model((*testModel)(nil)).Apply(tt.expr.Select)
+			_, err := q.AppendQuery(db.Formatter(), nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("AppendQuery() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
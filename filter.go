@@ -0,0 +1,320 @@
+package bunutils
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType is the value type of a filter field, filter values are coerced to it.
+type FieldType int
+
+const (
+	// FieldString is a string value.
+	FieldString FieldType = iota
+	// FieldInt is an int64 value.
+	FieldInt
+	// FieldFloat is a float64 value.
+	FieldFloat
+	// FieldBool is a strconv.ParseBool value.
+	FieldBool
+	// FieldTime is an RFC3339 or unix milliseconds time.Time value.
+	FieldTime
+)
+
+func (t FieldType) String() string {
+	switch t {
+	case FieldInt:
+		return "int"
+	case FieldFloat:
+		return "float"
+	case FieldBool:
+		return "bool"
+	case FieldTime:
+		return "time"
+	default:
+		return "string"
+	}
+}
+
+// FilterOp is an operator of a filter field.
+type FilterOp string
+
+// Filter operators, named as in filter[field][op] query string parameters.
+const (
+	FilterEq       FilterOp = "eq"
+	FilterNe       FilterOp = "ne"
+	FilterGt       FilterOp = "gt"
+	FilterGte      FilterOp = "gte"
+	FilterLt       FilterOp = "lt"
+	FilterLte      FilterOp = "lte"
+	FilterIn       FilterOp = "in"
+	FilterNotIn    FilterOp = "nin"
+	FilterContains FilterOp = "contains"
+	FilterBegins   FilterOp = "begins"
+	FilterEnds     FilterOp = "ends"
+	// FilterNull checks that the column is NULL for true value and NOT NULL for false value.
+	FilterNull FilterOp = "null"
+)
+
+// list reports whether the operator takes a list of values.
+func (op FilterOp) list() bool {
+	return op == FilterIn || op == FilterNotIn
+}
+
+// defaultOps returns the operators allowed for the field type if FilterField.Ops is empty.
+func (t FieldType) defaultOps() []FilterOp {
+	switch t {
+	case FieldString:
+		return []FilterOp{FilterEq, FilterNe, FilterIn, FilterNotIn, FilterContains, FilterBegins, FilterEnds, FilterNull}
+	case FieldBool:
+		return []FilterOp{FilterEq, FilterNe, FilterNull}
+	default:
+		return []FilterOp{FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterIn, FilterNotIn, FilterNull}
+	}
+}
+
+// FilterField describes a public filter field.
+type FilterField struct {
+	// Column is the column name, the public field name if empty.
+	Column string
+	// Type is the type the values are coerced to.
+	Type FieldType
+	// Ops are the allowed operators, all the operators of the Type if empty.
+	Ops []FilterOp
+}
+
+// FilterSchema maps public filter field names to columns, allowed operators and value types.
+// Fields which are not in the schema can't be filtered.
+//
+//	schema := bunutils.FilterSchema{
+//	    "status": {Ops: []bunutils.FilterOp{bunutils.FilterEq, bunutils.FilterIn}},
+//	    "age":    {Type: bunutils.FieldInt},
+//	    "name":   {Column: "full_name"},
+//	}
+type FilterSchema map[string]FilterField
+
+// Condition returns the condition of the field with the operator and values.
+// Values are coerced to the field type, list operators accept multiple values, the others exactly one.
+func (s FilterSchema) Condition(field string, op FilterOp, values ...string) (Condition, error) {
+	f, ok := s[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+
+	ops := f.Ops
+	if len(ops) == 0 {
+		ops = f.Type.defaultOps()
+	}
+	if !slices.Contains(ops, op) {
+		return nil, fmt.Errorf("operator %q is not allowed for field %q", op, field)
+	}
+
+	col := f.Column
+	if col == "" {
+		col = field
+	}
+
+	if op.list() {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("operator %q of field %q expects values", op, field)
+		}
+		list := make([]any, len(values))
+		for i, value := range values {
+			v, err := f.Type.coerce(value)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", field, err)
+			}
+			list[i] = v
+		}
+		if op == FilterNotIn {
+			return CondNotIn(col, list), nil
+		}
+		return CondIn(col, list), nil
+	}
+
+	if len(values) != 1 {
+		return nil, fmt.Errorf("operator %q of field %q expects a single value", op, field)
+	}
+
+	if op == FilterNull {
+		isNull, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return nil, fmt.Errorf("field %q: invalid bool value %q", field, values[0])
+		}
+		if isNull {
+			return CondNull(col), nil
+		}
+		return CondNotNull(col), nil
+	}
+
+	v, err := f.Type.coerce(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("field %q: %w", field, err)
+	}
+
+	switch op {
+	case FilterEq:
+		return CondEqual(col, v), nil
+	case FilterNe:
+		return CondNotEqual(col, v), nil
+	case FilterGt:
+		// Pointers keep zero values, which are skipped by comparison conditions.
+		return CondGt(col, pointerTo(v)), nil
+	case FilterGte:
+		return CondGte(col, pointerTo(v)), nil
+	case FilterLt:
+		return CondLt(col, pointerTo(v)), nil
+	case FilterLte:
+		return CondLte(col, pointerTo(v)), nil
+	case FilterContains:
+		return CondContains(col, values[0]), nil
+	case FilterBegins:
+		return CondBegins(col, values[0]), nil
+	case FilterEnds:
+		return CondEnds(col, values[0]), nil
+	default:
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+}
+
+// Selector is the Selector counterpart of FilterSchema.Condition.
+func (s FilterSchema) Selector(field string, op FilterOp, values ...string) (Selector, error) {
+	cond, err := s.Condition(field, op, values...)
+	if err != nil {
+		return nil, err
+	}
+	return cond.Select, nil
+}
+
+func (t FieldType) coerce(value string) (any, error) {
+	switch t {
+	case FieldInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int value %q", value)
+		}
+		return n, nil
+	case FieldFloat:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float value %q", value)
+		}
+		return n, nil
+	case FieldBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool value %q", value)
+		}
+		return b, nil
+	case FieldTime:
+		ms, err := parseTimestamp(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time value %q, expected RFC3339 or unix milliseconds", value)
+		}
+		return time.UnixMilli(ms).UTC(), nil
+	default:
+		return value, nil
+	}
+}
+
+func pointerTo(v any) any {
+	p := reflect.New(reflect.TypeOf(v))
+	p.Elem().Set(reflect.ValueOf(v))
+	return p.Interface()
+}
+
+// ParseFilter parses filter[field][op]=value query string parameters into a Selector
+// combining the conditions with AND:
+//
+//	?filter[status][eq]=active&filter[age][gte]=18&filter[name][contains]=jo
+//
+// filter[field]=value is a shorthand of the eq operator. List operators accept comma separated
+// and repeated values, filter[status][in]=active,pending. Other parameters are ignored.
+// Unknown fields, operators and invalid values are reported at once as ValidationErrors.
+func ParseFilter(values url.Values, schema FilterSchema) (Selector, error) {
+	cond, err := ParseFilterCondition(values, schema)
+	if err != nil {
+		return nil, err
+	}
+	return cond.Select, nil
+}
+
+// ParseFilterCondition is the Condition counterpart of ParseFilter.
+func ParseFilterCondition(values url.Values, schema FilterSchema) (Condition, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	// Stable order of conditions for the same query string.
+	slices.Sort(keys)
+
+	var (
+		conds []Condition
+		errs  ValidationErrors
+	)
+	for _, key := range keys {
+		field, op, ok := parseFilterKey(key)
+		if !ok {
+			errs = append(errs, FieldError{Field: key, Message: "expected filter[field] or filter[field][op]"})
+			continue
+		}
+
+		params := values[key]
+		if op.list() {
+			params = splitList(params)
+		}
+
+		cond, err := schema.Condition(field, op, params...)
+		if err != nil {
+			errs = append(errs, FieldError{Field: key, Message: err.Error()})
+			continue
+		}
+		conds = append(conds, cond)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return CondApply(conds...), nil
+}
+
+// parseFilterKey parses filter[field][op] and filter[field] keys.
+func parseFilterKey(key string) (string, FilterOp, bool) {
+	rest, ok := strings.CutPrefix(key, "filter[")
+	if !ok {
+		return "", "", false
+	}
+	field, rest, ok := strings.Cut(rest, "]")
+	if !ok || field == "" {
+		return "", "", false
+	}
+	if rest == "" {
+		return field, FilterEq, true
+	}
+
+	op, ok := strings.CutPrefix(rest, "[")
+	if !ok || !strings.HasSuffix(op, "]") || len(op) < 2 {
+		return "", "", false
+	}
+	return field, FilterOp(strings.TrimSuffix(op, "]")), true
+}
+
+// splitList splits comma separated values, empty items are skipped.
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
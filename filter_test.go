@@ -0,0 +1,121 @@
+package bunutils
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+var testFilterSchema = FilterSchema{
+	"status":  {Ops: []FilterOp{FilterEq, FilterIn}},
+	"age":     {Type: FieldInt},
+	"name":    {Column: "full_name"},
+	"active":  {Type: FieldBool},
+	"created": {Column: "created_at", Type: FieldTime},
+}
+
+func TestParseFilter(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "operators",
+			query: "filter[status][eq]=active&filter[age][gte]=18&filter[name][contains]=jo",
+			want:  `WHERE ("test_model"."age" >= 18) AND ("test_model"."full_name" ILIKE '%jo%' ESCAPE '\') AND ("test_model"."status" = 'active')`,
+		},
+		{
+			name:  "shorthand",
+			query: "filter[active]=true",
+			want:  `WHERE ("test_model"."active" = TRUE)`,
+		},
+		{
+			name:  "list",
+			query: "filter[status][in]=active,pending&filter[status][in]=new",
+			want:  `WHERE ("test_model"."status" IN ('active', 'pending', 'new'))`,
+		},
+		{
+			name:  "zero value",
+			query: "filter[age][gt]=0",
+			want:  `WHERE ("test_model"."age" > 0)`,
+		},
+		{
+			name:  "null",
+			query: "filter[name][null]=false",
+			want:  `WHERE ("test_model"."full_name" is not null)`,
+		},
+		{
+			name:  "time",
+			query: "filter[created][lt]=2025-01-01T00:00:00Z",
+			want:  `WHERE ("test_model"."created_at" < '2025-01-01 00:00:00+00:00')`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			selector, err := ParseFilter(values, testFilterSchema)
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+
+			sql := selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		values, _ := url.ParseQuery("filter[password][eq]=x&filter[status][contains]=a&filter[age][gte]=old&filter[age][eq]=1&filter[age][eq]=2&filter[broken=1&page=2")
+		_, err := ParseFilter(values, testFilterSchema)
+
+		var errs ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("ParseFilter() error = %v, want ValidationErrors", err)
+		}
+
+		msg := err.Error()
+		for _, want := range []string{
+			`unknown field "password"`,
+			`operator "contains" is not allowed for field "status"`,
+			`invalid int value "old"`,
+			`operator "eq" of field "age" expects a single value`,
+			`filter[broken: expected filter[field] or filter[field][op]`,
+		} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("ParseFilter() error = %s, want it to contain %s", msg, want)
+			}
+		}
+		if len(errs) != 5 {
+			t.Errorf("ParseFilter() returned %d errors, want 5", len(errs))
+		}
+	})
+}
+
+func TestParseFilterKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		field string
+		op    FilterOp
+		ok    bool
+	}{
+		{key: "filter[status][eq]", field: "status", op: FilterEq, ok: true},
+		{key: "filter[status]", field: "status", op: FilterEq, ok: true},
+		{key: "filter[]", ok: false},
+		{key: "filter[status][]", ok: false},
+		{key: "filter[status]eq", ok: false},
+	}
+
+	for _, tt := range tests {
+		field, op, ok := parseFilterKey(tt.key)
+		if field != tt.field || op != tt.op || ok != tt.ok {
+			t.Errorf("parseFilterKey(%q) = %q, %q, %v", tt.key, field, op, ok)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package bunutils
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// FilterDocSchema is the JSON Schema (draft 2020-12) of FilterDoc.
+//
+//go:embed filterdoc.schema.json
+var FilterDocSchema []byte
+
+// FilterDoc is a JSON filter document, a tree of and, or, not groups and field comparisons:
+//
+//	{"and": [
+//	    {"field": "status", "op": "eq", "value": "active"},
+//	    {"or": [
+//	        {"field": "age", "op": "gte", "value": 18},
+//	        {"field": "role", "op": "in", "value": ["admin", "owner"]}
+//	    ]}
+//	]}
+//
+// A node has exactly one of And, Or, Not or Field. Comparisons use the operators and value types
+// of FilterSchema, values are strings, numbers, booleans or arrays of them for list operators.
+// Documents can be stored and passed between services, see FilterDocSchema.
+type FilterDoc struct {
+	And []FilterDoc `json:"and,omitempty"`
+	Or  []FilterDoc `json:"or,omitempty"`
+	Not *FilterDoc  `json:"not,omitempty"`
+
+	Field string   `json:"field,omitempty"`
+	Op    FilterOp `json:"op,omitempty"`
+	Value any      `json:"value,omitempty"`
+}
+
+// DocAnd returns a document in which all the documents must match.
+func DocAnd(docs ...FilterDoc) FilterDoc {
+	return FilterDoc{And: docs}
+}
+
+// DocOr returns a document in which any of the documents must match.
+func DocOr(docs ...FilterDoc) FilterDoc {
+	return FilterDoc{Or: docs}
+}
+
+// DocNot returns a document negating the document.
+func DocNot(doc FilterDoc) FilterDoc {
+	return FilterDoc{Not: &doc}
+}
+
+// DocField returns a comparison of the field with the value, a slice for list operators.
+//
+//	bunutils.DocAnd(
+//	    bunutils.DocField("status", bunutils.FilterEq, "active"),
+//	    bunutils.DocField("role", bunutils.FilterIn, []string{"admin", "owner"}),
+//	)
+func DocField(field string, op FilterOp, value any) FilterDoc {
+	return FilterDoc{Field: field, Op: op, Value: value}
+}
+
+// UnmarshalJSON decodes the document rejecting unknown keys. Numbers are decoded as json.Number,
+// so int64 values keep their precision.
+func (d *FilterDoc) UnmarshalJSON(b []byte) error {
+	type doc FilterDoc
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	return dec.Decode((*doc)(d))
+}
+
+// ParseFilterDoc decodes JSON filter document and compiles it into a Selector, see FilterDoc.Selector.
+func ParseFilterDoc(data []byte, schema FilterSchema) (Selector, error) {
+	var doc FilterDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("bunutils: invalid filter document: %w", err)
+	}
+	return doc.Selector(schema)
+}
+
+// Selector compiles the document into a Selector with the fields of the schema.
+// See FilterDoc.Condition.
+func (d FilterDoc) Selector(schema FilterSchema) (Selector, error) {
+	cond, err := d.Condition(schema)
+	if err != nil {
+		return nil, err
+	}
+	return cond.Select, nil
+}
+
+// Condition compiles the document into a Condition with the fields of the schema:
+// and into CondAndGroup, or into CondOr, not into CondNot and comparisons with FilterSchema.Condition.
+// All invalid nodes are reported at once as ValidationErrors with JSON paths of the nodes, e.g. $.and[1].or[0].
+func (d FilterDoc) Condition(schema FilterSchema) (Condition, error) {
+	var errs ValidationErrors
+	cond := d.compile(schema, "$", &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return cond, nil
+}
+
+func (d FilterDoc) compile(schema FilterSchema, path string, errs *ValidationErrors) Condition {
+	fail := func(format string, args ...any) Condition {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+		return nil
+	}
+
+	kinds := 0
+	for _, set := range []bool{d.And != nil, d.Or != nil, d.Not != nil, d.Field != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fail("expected exactly one of and, or, not or field")
+	}
+
+	switch {
+	case d.And != nil || d.Or != nil:
+		key, docs := "and", d.And
+		if d.Or != nil {
+			key, docs = "or", d.Or
+		}
+		if len(docs) == 0 {
+			return fail("%s must not be empty", key)
+		}
+
+		conds := make([]Condition, len(docs))
+		for i, doc := range docs {
+			conds[i] = doc.compile(schema, fmt.Sprintf("%s.%s[%d]", path, key, i), errs)
+		}
+		if key == "or" {
+			return CondOr(conds...)
+		}
+		return CondAndGroup(conds...)
+	case d.Not != nil:
+		return CondNot(d.Not.compile(schema, path+".not", errs))
+	}
+
+	values, err := filterDocValues(d.Value)
+	if err != nil {
+		return fail("%v", err)
+	}
+	cond, err := schema.Condition(d.Field, d.Op, values...)
+	if err != nil {
+		return fail("%v", err)
+	}
+	return cond
+}
+
+// filterDocValues formats the comparison value as FilterSchema.Condition values.
+func filterDocValues(value any) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if _, ok := value.([]byte); !ok && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) {
+		values := make([]string, v.Len())
+		for i := range values {
+			s, err := filterDocValue(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[i] = s
+		}
+		return values, nil
+	}
+
+	s, err := filterDocValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+func filterDocValue(value any) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	case encoding.TextMarshaler:
+		b, err := value.MarshalText()
+		return string(b), err
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", value)
+	}
+}
@@ -0,0 +1,133 @@
+package bunutils
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterDoc(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	data := `{"and":[{"field":"status","op":"eq","value":"active"},{"or":[{"field":"age","op":"gte","value":18},{"field":"status","op":"in","value":["new","pending"]}]},{"not":{"field":"name","op":"null","value":true}}]}`
+	selector, err := ParseFilterDoc([]byte(data), testFilterSchema)
+	if err != nil {
+		t.Fatalf("ParseFilterDoc() error = %v", err)
+	}
+
+	sql := selector(db.NewSelect().Model((*testModel)(nil))).String()
+	want := `WHERE (("test_model"."status" = 'active') AND ((("test_model"."age" >= 18)) OR (("test_model"."status" IN ('new', 'pending')))) AND NOT (("test_model"."full_name" is null)))`
+	if !strings.Contains(sql, want) {
+		t.Errorf("got %s, want it to contain %s", sql, want)
+	}
+}
+
+func TestFilterDoc_RoundTrip(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc := DocAnd(
+		DocField("status", FilterIn, []string{"active", "new"}),
+		DocOr(
+			DocField("age", FilterGt, 9007199254740993),
+			DocField("created", FilterLt, created),
+		),
+		DocNot(DocField("active", FilterEq, false)),
+	)
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"and":[{"field":"status","op":"in","value":["active","new"]},{"or":[{"field":"age","op":"gt","value":9007199254740993},{"field":"created","op":"lt","value":"2025-01-02T03:04:05Z"}]},{"not":{"field":"active","op":"eq","value":false}}]}`
+	if string(data) != want {
+		t.Fatalf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded FilterDoc
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	again, _ := json.Marshal(decoded)
+	if string(again) != want {
+		t.Errorf("round trip = %s, want %s", again, want)
+	}
+
+	built, err := doc.Selector(testFilterSchema)
+	if err != nil {
+		t.Fatalf("Selector() error = %v", err)
+	}
+	parsed, err := decoded.Selector(testFilterSchema)
+	if err != nil {
+		t.Fatalf("Selector() error = %v", err)
+	}
+
+	builtSQL := built(db.NewSelect().Model((*testModel)(nil))).String()
+	parsedSQL := parsed(db.NewSelect().Model((*testModel)(nil))).String()
+	if builtSQL != parsedSQL {
+		t.Errorf("built %s, parsed %s", builtSQL, parsedSQL)
+	}
+	if !strings.Contains(parsedSQL, `"test_model"."age" > 9007199254740993`) {
+		t.Errorf("numbers should keep precision, got %s", parsedSQL)
+	}
+}
+
+func TestFilterDoc_Errors(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		data := `{"and":[{"field":"password","op":"eq","value":"x"},{"or":[]},{"field":"age","op":"gte","value":"old"},{"field":"age","and":[]},{"not":{"field":"status","op":"contains","value":"a"}}]}`
+		_, err := ParseFilterDoc([]byte(data), testFilterSchema)
+
+		var errs ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("ParseFilterDoc() error = %v, want ValidationErrors", err)
+		}
+
+		want := ValidationErrors{
+			{Field: "$.and[0]", Message: `unknown field "password"`},
+			{Field: "$.and[1]", Message: "or must not be empty"},
+			{Field: "$.and[2]", Message: `field "age": invalid int value "old"`},
+			{Field: "$.and[3]", Message: "expected exactly one of and, or, not or field"},
+			{Field: "$.and[4].not", Message: `operator "contains" is not allowed for field "status"`},
+		}
+		if !slices.Equal(errs, want) {
+			t.Errorf("ParseFilterDoc() errors = %v, want %v", errs, want)
+		}
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		_, err := ParseFilterDoc([]byte(`{"field":"age","op":"eq","value":1,"where":"1=1"}`), testFilterSchema)
+		if err == nil || !strings.Contains(err.Error(), `unknown field "where"`) {
+			t.Errorf("ParseFilterDoc() error = %v, want unknown key error", err)
+		}
+	})
+
+	t.Run("value types", func(t *testing.T) {
+		_, err := DocField("status", FilterEq, map[string]string{}).Condition(testFilterSchema)
+		if err == nil || !strings.Contains(err.Error(), "unsupported value type") {
+			t.Errorf("Condition() error = %v, want unsupported value type", err)
+		}
+	})
+}
+
+func TestFilterDocSchema(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]struct {
+				Enum []FilterOp `json:"enum"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(FilterDocSchema, &schema); err != nil {
+		t.Fatalf("FilterDocSchema is not valid JSON: %v", err)
+	}
+
+	ops := []FilterOp{FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterIn, FilterNotIn, FilterContains, FilterBegins, FilterEnds, FilterNull}
+	if got := schema.Defs["comparison"].Properties["op"].Enum; !slices.Equal(got, ops) {
+		t.Errorf("FilterDocSchema operators = %v, want %v", got, ops)
+	}
+}
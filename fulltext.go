@@ -0,0 +1,197 @@
+package bunutils
+
+import (
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// FullTextMode defines how the search query is parsed into tsquery.
+type FullTextMode int
+
+const (
+	// FullTextWebsearch parses the query with websearch_to_tsquery, supports "quoted phrases", OR and -exclusions.
+	FullTextWebsearch FullTextMode = iota
+	// FullTextPlain parses the query with plainto_tsquery, all words are required.
+	FullTextPlain
+	// FullTextPhrase parses the query with phraseto_tsquery, words are required to follow each other.
+	FullTextPhrase
+)
+
+func (m FullTextMode) function() string {
+	switch m {
+	case FullTextPlain:
+		return "plainto_tsquery"
+	case FullTextPhrase:
+		return "phraseto_tsquery"
+	default:
+		return "websearch_to_tsquery"
+	}
+}
+
+// FullTextDocument is a document searched by full-text selectors,
+// either a stored tsvector column or an expression over text columns.
+type FullTextDocument struct {
+	vector  string
+	columns []string
+}
+
+// TsVector refers to a stored tsvector column.
+func TsVector(col string) FullTextDocument {
+	return FullTextDocument{vector: col}
+}
+
+// ToTsVector builds a tsvector from text columns with to_tsvector. NULL columns are skipped.
+func ToTsVector(cols ...string) FullTextDocument {
+	return FullTextDocument{columns: cols}
+}
+
+func (d FullTextDocument) expression(q bun.QueryBuilder, o fullTextOptions) (string, []any) {
+	if d.vector != "" {
+		return "?TableAlias.?", []any{column(q, d.vector)}
+	}
+
+	expr, args := o.configArg()
+	expr = "to_tsvector(" + expr + "concat_ws(' '"
+	for _, col := range d.columns {
+		expr += ", ?TableAlias.?"
+		args = append(args, column(q, col))
+	}
+	return expr + "))", args
+}
+
+type fullTextOptions struct {
+	config    string
+	mode      FullTextMode
+	headline  string
+	normalize int
+}
+
+// configArg returns the regconfig argument of text search functions, empty for default_text_search_config.
+func (o fullTextOptions) configArg() (string, []any) {
+	if o.config == "" {
+		return "", nil
+	}
+	return "?::regconfig, ", []any{o.config}
+}
+
+func (o fullTextOptions) tsQuery(query string) (string, []any) {
+	expr, args := o.configArg()
+	return o.mode.function() + "(" + expr + "?)", append(args, query)
+}
+
+// FullTextOption configures full-text search selectors.
+type FullTextOption func(*fullTextOptions)
+
+// WithConfig sets text search configuration, e.g. "english".
+// The server default_text_search_config is used if not set.
+func WithConfig(config string) FullTextOption {
+	return func(o *fullTextOptions) {
+		o.config = config
+	}
+}
+
+// WithMode sets how the search query is parsed, FullTextWebsearch by default.
+func WithMode(mode FullTextMode) FullTextOption {
+	return func(o *fullTextOptions) {
+		o.mode = mode
+	}
+}
+
+// WithHeadlineOptions sets ts_headline options, e.g. "MaxWords=35, MinWords=15, StartSel=<b>, StopSel=</b>".
+func WithHeadlineOptions(options string) FullTextOption {
+	return func(o *fullTextOptions) {
+		o.headline = options
+	}
+}
+
+// WithRankNormalization sets ts_rank normalization bit mask, e.g. 32 to scale the rank into 0..1 range.
+func WithRankNormalization(normalization int) FullTextOption {
+	return func(o *fullTextOptions) {
+		o.normalize = normalization
+	}
+}
+
+func newFullTextOptions(opts []FullTextOption) fullTextOptions {
+	var o fullTextOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// CondFullText is the Condition counterpart of WhereFullText.
+func CondFullText(doc FullTextDocument, query string, opts ...FullTextOption) Condition {
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+
+	o := newFullTextOptions(opts)
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		docExpr, args := doc.expression(q, o)
+		queryExpr, queryArgs := o.tsQuery(query)
+		return q.Where(docExpr+" @@ "+queryExpr, append(args, queryArgs...)...)
+	}
+}
+
+// WhereFullText matches the document against the search query with the @@ operator. Empty query is skipped.
+//
+// Note: PostgreSQL only.
+func WhereFullText(doc FullTextDocument, query string, opts ...FullTextOption) Selector {
+	return CondFullText(doc, query, opts...).Selector()
+}
+
+// OrderByFullTextRank orders rows by ts_rank of the document against the search query, best matches first.
+// Empty query is skipped.
+//
+// Note: PostgreSQL only.
+func OrderByFullTextRank(doc FullTextDocument, query string, opts ...FullTextOption) Selector {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if strings.TrimSpace(query) == "" {
+			return q
+		}
+
+		o := newFullTextOptions(opts)
+		docExpr, args := doc.expression(q.QueryBuilder(), o)
+		queryExpr, queryArgs := o.tsQuery(query)
+		args = append(args, queryArgs...)
+
+		expr := "ts_rank(" + docExpr + ", " + queryExpr
+		if o.normalize != 0 {
+			expr += ", ?"
+			args = append(args, o.normalize)
+		}
+		return q.OrderExpr(expr+") DESC", args...)
+	}
+}
+
+// SelectFullTextHeadline adds ts_headline snippet of the text column, with matches of the search query highlighted,
+// as a column with the provided alias. Empty query is skipped.
+// The selected columns are replaced the same way as with bun.SelectQuery.ColumnExpr,
+// add Column or ColumnExpr("?TableColumns") to keep the model columns.
+//
+// Note: PostgreSQL only.
+func SelectFullTextHeadline(col string, query string, alias string, opts ...FullTextOption) Selector {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if strings.TrimSpace(query) == "" {
+			return q
+		}
+
+		o := newFullTextOptions(opts)
+		expr, args := o.configArg()
+		expr = "ts_headline(" + expr + "?TableAlias.?, "
+		args = append(args, column(q.QueryBuilder(), col))
+
+		queryExpr, queryArgs := o.tsQuery(query)
+		expr += queryExpr
+		args = append(args, queryArgs...)
+
+		if o.headline != "" {
+			expr += ", ?"
+			args = append(args, o.headline)
+		}
+		return q.ColumnExpr(expr+") AS ?", append(args, bun.Ident(alias))...)
+	}
+}
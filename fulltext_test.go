@@ -0,0 +1,105 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWhereFullText(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "tsvector column",
+			selector: WhereFullText(TsVector("search"), "cats -dogs"),
+			want:     `"test_model"."search" @@ websearch_to_tsquery('cats -dogs')`,
+		},
+		{
+			name:     "with config",
+			selector: WhereFullText(TsVector("search"), "cats", WithConfig("english")),
+			want:     `"test_model"."search" @@ websearch_to_tsquery('english'::regconfig, 'cats')`,
+		},
+		{
+			name:     "plain mode",
+			selector: WhereFullText(TsVector("search"), "cats", WithMode(FullTextPlain)),
+			want:     `@@ plainto_tsquery('cats')`,
+		},
+		{
+			name:     "phrase mode",
+			selector: WhereFullText(TsVector("search"), "fat cats", WithMode(FullTextPhrase)),
+			want:     `@@ phraseto_tsquery('fat cats')`,
+		},
+		{
+			name:     "text columns",
+			selector: WhereFullText(ToTsVector("title", "body"), "cats", WithConfig("english")),
+			want:     `to_tsvector('english'::regconfig, concat_ws(' ', "test_model"."title", "test_model"."body")) @@ websearch_to_tsquery('english'::regconfig, 'cats')`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty query", func(t *testing.T) {
+		selector := WhereFullText(TsVector("search"), " ")
+		if selector != nil {
+			t.Error("WhereFullText() should give a nil Selector for empty query")
+		}
+		sql := db.NewSelect().Model((*testModel)(nil)).Apply(selector).String()
+		if strings.Contains(sql, "WHERE") {
+			t.Errorf("WhereFullText() should skip empty query, got %s", sql)
+		}
+	})
+}
+
+func TestOrderByFullTextRank(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	selector := OrderByFullTextRank(TsVector("search"), "cats", WithConfig("english"), WithRankNormalization(32))
+
+	sql := selector(query).String()
+	want := `ORDER BY ts_rank("test_model"."search", websearch_to_tsquery('english'::regconfig, 'cats'), 32) DESC`
+
+	if !strings.Contains(sql, want) {
+		t.Errorf("OrderByFullTextRank() = %s, want it to contain %s", sql, want)
+	}
+}
+
+func TestSelectFullTextHeadline(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil)).ColumnExpr("?TableColumns")
+	selector := SelectFullTextHeadline("body", "cats", "snippet", WithHeadlineOptions("MaxWords=10"))
+
+	sql := selector(query).String()
+	want := `ts_headline("test_model"."body", websearch_to_tsquery('cats'), 'MaxWords=10') AS "snippet"`
+
+	if !strings.Contains(sql, want) || !strings.Contains(sql, `"test_model"."name"`) {
+		t.Errorf("SelectFullTextHeadline() = %s, want it to contain %s", sql, want)
+	}
+}
+
+func TestCondFullText(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewDelete().Model((*testModel)(nil))
+	sql := CondFullText(TsVector("search"), "spam").Delete(query).String()
+
+	if !strings.Contains(sql, `WHERE ("test_model"."search" @@ websearch_to_tsquery('spam'))`) {
+		t.Errorf("CondFullText() should add condition to DELETE, got %s", sql)
+	}
+}
@@ -0,0 +1,167 @@
+package bunutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// CondJsonbContains is the Condition counterpart of WhereJsonbContains.
+func CondJsonbContains(col string, path []string, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return setQueryErr(q, fmt.Errorf("bunutils: marshal jsonb value: %w", err))
+		}
+		expr, args := jsonbPathExpression(q, col, path, false)
+		return q.Where(expr+" @> ?::jsonb", append(args, string(b))...)
+	}
+}
+
+// CondJsonbHasKey is the Condition counterpart of WhereJsonbHasKey.
+func CondJsonbHasKey(col string, path []string, key string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		expr, args := jsonbPathExpression(q, col, path, false)
+		return q.Where(expr+` \? ?`, append(args, key)...)
+	}
+}
+
+// CondJsonbHasAnyKeys is the Condition counterpart of WhereJsonbHasAnyKeys.
+func CondJsonbHasAnyKeys(col string, path []string, keys ...string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		expr, args := jsonbPathExpression(q, col, path, false)
+		return q.Where(expr+` \?| ?`, append(args, pgdialect.Array(keys))...)
+	}
+}
+
+// CondJsonbHasAllKeys is the Condition counterpart of WhereJsonbHasAllKeys.
+func CondJsonbHasAllKeys(col string, path []string, keys ...string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		expr, args := jsonbPathExpression(q, col, path, false)
+		return q.Where(expr+` \?& ?`, append(args, pgdialect.Array(keys))...)
+	}
+}
+
+// CondJsonbPathExists is the Condition counterpart of WhereJsonbPathExists.
+func CondJsonbPathExists(col string, jsonpath string, vars map[string]any) Condition {
+	return jsonPathCondition(col, jsonpath, vars, `@\?`, "jsonb_path_exists")
+}
+
+// CondJsonbPathMatch is the Condition counterpart of WhereJsonbPathMatch.
+func CondJsonbPathMatch(col string, jsonpath string, vars map[string]any) Condition {
+	return jsonPathCondition(col, jsonpath, vars, "@@", "jsonb_path_match")
+}
+
+func jsonPathCondition(col string, jsonpath string, vars map[string]any, op string, function string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		if len(vars) == 0 {
+			// Operators can use GIN indexes, but don't accept variables.
+			return q.Where("?TableAlias.? "+op+" ?::jsonpath", column(q, col), jsonpath)
+		}
+
+		b, err := json.Marshal(vars)
+		if err != nil {
+			return setQueryErr(q, fmt.Errorf("bunutils: marshal jsonpath variables: %w", err))
+		}
+		return q.Where(function+"(?TableAlias.?, ?::jsonpath, ?::jsonb)", column(q, col), jsonpath, string(b))
+	}
+}
+
+// CondJsonbPathNumber is the Condition counterpart of WhereJsonbPathNumber.
+func CondJsonbPathNumber(col string, path []string, op CompareOp, value any) Condition {
+	return jsonbPathCompareCondition(col, path, "numeric", op, value)
+}
+
+// CondJsonbPathBool is the Condition counterpart of WhereJsonbPathBool.
+func CondJsonbPathBool(col string, path []string, value bool) Condition {
+	return jsonbPathCompareCondition(col, path, "boolean", OpEqual, value)
+}
+
+// CondJsonbPathTime is the Condition counterpart of WhereJsonbPathTime.
+func CondJsonbPathTime(col string, path []string, op CompareOp, t time.Time) Condition {
+	return jsonbPathCompareCondition(col, path, "timestamptz", op, t)
+}
+
+func jsonbPathCompareCondition(col string, path []string, cast string, op CompareOp, value any) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		if !op.Valid() {
+			return setQueryErr(q, fmt.Errorf("bunutils: unsupported jsonb comparison operator %q", op))
+		}
+		expr, args := jsonbPathExpression(q, col, path, true)
+		return q.Where("("+expr+")::"+cast+" "+string(op)+" ?", append(args, value)...)
+	}
+}
+
+// WhereJsonbContains checks that the JSONB value at path contains the JSON encoded value (@>).
+// Empty path refers to the column itself.
+//
+// Note: PostgreSQL only.
+func WhereJsonbContains(col string, path []string, value any) Selector {
+	return CondJsonbContains(col, path, value).Select
+}
+
+// WhereJsonbHasKey checks that the JSONB object at path has the key (?).
+// Empty path refers to the column itself.
+//
+// Note: PostgreSQL only.
+func WhereJsonbHasKey(col string, path []string, key string) Selector {
+	return CondJsonbHasKey(col, path, key).Select
+}
+
+// WhereJsonbHasAnyKeys checks that the JSONB object at path has any of the keys (?|).
+// Empty path refers to the column itself.
+//
+// Note: PostgreSQL only.
+func WhereJsonbHasAnyKeys(col string, path []string, keys ...string) Selector {
+	return CondJsonbHasAnyKeys(col, path, keys...).Select
+}
+
+// WhereJsonbHasAllKeys checks that the JSONB object at path has all the keys (?&).
+// Empty path refers to the column itself.
+//
+// Note: PostgreSQL only.
+func WhereJsonbHasAllKeys(col string, path []string, keys ...string) Selector {
+	return CondJsonbHasAllKeys(col, path, keys...).Select
+}
+
+// WhereJsonbPathExists checks that the jsonpath returns any item for the column (@?).
+// Variables are referenced in jsonpath as $name and passed JSON encoded,
+// jsonb_path_exists is used instead of the operator if there are any.
+//
+// Note: PostgreSQL only.
+func WhereJsonbPathExists(col string, jsonpath string, vars map[string]any) Selector {
+	return CondJsonbPathExists(col, jsonpath, vars).Select
+}
+
+// WhereJsonbPathMatch checks that the jsonpath predicate is true for the column (@@).
+// Variables are referenced in jsonpath as $name and passed JSON encoded,
+// jsonb_path_match is used instead of the operator if there are any.
+//
+// Note: PostgreSQL only.
+func WhereJsonbPathMatch(col string, jsonpath string, vars map[string]any) Selector {
+	return CondJsonbPathMatch(col, jsonpath, vars).Select
+}
+
+// WhereJsonbPathNumber compares the JSONB value at path cast to numeric with the value.
+//
+// Note: PostgreSQL only.
+func WhereJsonbPathNumber(col string, path []string, op CompareOp, value any) Selector {
+	return CondJsonbPathNumber(col, path, op, value).Select
+}
+
+// WhereJsonbPathBool compares the JSONB value at path cast to boolean with the value.
+//
+// Note: PostgreSQL only.
+func WhereJsonbPathBool(col string, path []string, value bool) Selector {
+	return CondJsonbPathBool(col, path, value).Select
+}
+
+// WhereJsonbPathTime compares the JSONB value at path cast to timestamptz with the time.
+//
+// Note: PostgreSQL only.
+func WhereJsonbPathTime(col string, path []string, op CompareOp, t time.Time) Selector {
+	return CondJsonbPathTime(col, path, op, t).Select
+}
@@ -0,0 +1,165 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWhereJsonbContains(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "column",
+			selector: WhereJsonbContains("metadata", nil, map[string]any{"count": 1, "active": true}),
+			want:     `"test_model"."metadata" @> '{"active":true,"count":1}'::jsonb`,
+		},
+		{
+			name:     "path",
+			selector: WhereJsonbContains("metadata", []string{"user", "roles"}, []string{"admin"}),
+			want:     `"test_model"."metadata" #> '{"user","roles"}' @> '["admin"]'::jsonb`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("marshal error", func(t *testing.T) {
+		result := WhereJsonbContains("metadata", nil, make(chan int))(db.NewSelect().Model((*testModel)(nil)))
+		if _, err := result.AppendQuery(db.Formatter(), nil); err == nil {
+			t.Error("WhereJsonbContains() should fail with value which can't be marshalled")
+		}
+	})
+}
+
+func TestWhereJsonbHasKeys(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "has key",
+			selector: WhereJsonbHasKey("metadata", nil, "email"),
+			want:     `"test_model"."metadata" ? 'email'`,
+		},
+		{
+			name:     "has any keys",
+			selector: WhereJsonbHasAnyKeys("metadata", []string{"contacts"}, "email", "phone"),
+			want:     `"test_model"."metadata" #> '{"contacts"}' ?| '{"email","phone"}'`,
+		},
+		{
+			name:     "has all keys",
+			selector: WhereJsonbHasAllKeys("metadata", nil, "email", "phone"),
+			want:     `"test_model"."metadata" ?& '{"email","phone"}'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereJsonbPath(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "exists",
+			selector: WhereJsonbPathExists("metadata", "$.tags[*] ? (@ == \"go\")", nil),
+			want:     `"test_model"."metadata" @? '$.tags[*] ? (@ == "go")'::jsonpath`,
+		},
+		{
+			name:     "exists with variables",
+			selector: WhereJsonbPathExists("metadata", "$.price ? (@ > $min)", map[string]any{"min": 10}),
+			want:     `jsonb_path_exists("test_model"."metadata", '$.price ? (@ > $min)'::jsonpath, '{"min":10}'::jsonb)`,
+		},
+		{
+			name:     "match",
+			selector: WhereJsonbPathMatch("metadata", "$.price > 10", nil),
+			want:     `"test_model"."metadata" @@ '$.price > 10'::jsonpath`,
+		},
+		{
+			name:     "match with variables",
+			selector: WhereJsonbPathMatch("metadata", "$.price > $min", map[string]any{"min": 10}),
+			want:     `jsonb_path_match("test_model"."metadata", '$.price > $min'::jsonpath, '{"min":10}'::jsonb)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereJsonbPathTyped(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "number",
+			selector: WhereJsonbPathNumber("metadata", []string{"order", "total"}, OpGreaterOrEqual, 10.5),
+			want:     `("test_model"."metadata" #>> '{"order","total"}')::numeric >= 10.5`,
+		},
+		{
+			name:     "bool",
+			selector: WhereJsonbPathBool("metadata", []string{"active"}, true),
+			want:     `("test_model"."metadata" #>> '{"active"}')::boolean = TRUE`,
+		},
+		{
+			name:     "time",
+			selector: WhereJsonbPathTime("metadata", []string{"paid_at"}, OpLess, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			want:     `("test_model"."metadata" #>> '{"paid_at"}')::timestamptz < '2025-01-01 00:00:00+00:00'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid operator", func(t *testing.T) {
+		result := WhereJsonbPathNumber("metadata", []string{"total"}, CompareOp("LIKE"), 1)(db.NewSelect().Model((*testModel)(nil)))
+		if _, err := result.AppendQuery(db.Formatter(), nil); err == nil {
+			t.Error("WhereJsonbPathNumber() should fail with invalid operator")
+		}
+	})
+}
@@ -0,0 +1,324 @@
+package bunutils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// DefaultKeysetLimit is the page size of keyset pagination without Limit.
+const DefaultKeysetLimit = 20
+
+// ErrInvalidCursor is returned for cursors which can't be decoded, have invalid signature
+// or don't match the sort columns of the Keyset.
+var ErrInvalidCursor = errors.New("bunutils: invalid cursor")
+
+// SortColumn is a column of keyset pagination order.
+type SortColumn struct {
+	Col  string
+	Desc bool
+}
+
+// Asc orders by the column in ascending order.
+func Asc(col string) SortColumn {
+	return SortColumn{Col: col}
+}
+
+// Desc orders by the column in descending order.
+func Desc(col string) SortColumn {
+	return SortColumn{Col: col, Desc: true}
+}
+
+// Keyset is keyset (cursor) pagination. Instead of OFFSET, rows are filtered by the sort column values
+// of the last row of the previous page, so pages stay fast and stable while the table is written.
+// Sort columns must be NOT NULL, KeysetCursors fails for NULL values. IDCol is added as the last sort column
+// to make the order unique. Cursors are bound to the sort columns and directions.
+//
+//	ks := &bunutils.Keyset{Columns: []bunutils.SortColumn{bunutils.Desc("created_at")}, Limit: 50, Cursor: cursor}
+//	err := db.NewSelect().Model(&users).Apply(ks.Select).Scan(ctx)
+//	users, next, prev, err := bunutils.KeysetCursors(db, ks, users)
+type Keyset struct {
+	// Columns is the order of rows.
+	Columns []SortColumn
+	// IDCol is the unique tiebreaker column, DefaultIDCol if empty.
+	IDCol string
+	// Limit is the page size, DefaultKeysetLimit if not positive.
+	Limit int
+	// Cursor is the next or prev cursor of the previous page, empty for the first page.
+	Cursor string
+	// Secret signs cursors with HMAC-SHA256 if set, otherwise cursors are only base64 encoded.
+	Secret []byte
+}
+
+// Cursor is the decoded position of keyset pagination.
+type Cursor struct {
+	// Values are the sort column values of the row, including the tiebreaker.
+	Values []any `json:"v"`
+	// Backward is set for prev cursors, which select rows before the row.
+	Backward bool `json:"b,omitempty"`
+	// Sort identifies the sort columns and directions of the Keyset, it is set by EncodeCursor
+	// and checked by DecodeCursor, so cursors of another order are rejected.
+	Sort string `json:"s,omitempty"`
+}
+
+// columns returns the sort columns with the tiebreaker.
+func (k *Keyset) columns() []SortColumn {
+	idCol := k.IDCol
+	if idCol == "" {
+		idCol = DefaultIDCol
+	}
+
+	cols := make([]SortColumn, 0, len(k.Columns)+1)
+	for _, col := range k.Columns {
+		if col.Col == idCol {
+			return append(cols, col)
+		}
+		cols = append(cols, col)
+	}
+
+	tiebreaker := SortColumn{Col: idCol}
+	if len(cols) > 0 {
+		tiebreaker.Desc = cols[len(cols)-1].Desc
+	}
+	return append(cols, tiebreaker)
+}
+
+// sortKey returns a short hash of the sort columns and directions with the tiebreaker.
+func (k *Keyset) sortKey() string {
+	h := sha256.New()
+	for _, col := range k.columns() {
+		fmt.Fprintf(h, "%q %t;", col.Col, col.Desc)
+	}
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:8])
+}
+
+func (k *Keyset) limit() int {
+	if k.Limit <= 0 {
+		return DefaultKeysetLimit
+	}
+	return k.Limit
+}
+
+// Select filters rows after the Cursor, orders them and limits the page.
+// One extra row is selected to detect whether there are more pages, see KeysetCursors.
+// Invalid cursors fail the query with ErrInvalidCursor.
+func (k *Keyset) Select(q *bun.SelectQuery) *bun.SelectQuery {
+	if k == nil {
+		return q
+	}
+
+	cols := k.columns()
+
+	var cursor Cursor
+	if k.Cursor != "" {
+		var err error
+		if cursor, err = k.DecodeCursor(k.Cursor); err != nil {
+			return q.Err(err)
+		}
+		expr, args := keysetExpression(cols, cursor.Values, cursor.Backward)
+		q = q.Where(expr, args...)
+	}
+
+	for _, col := range cols {
+		if col.Desc != cursor.Backward {
+			q = q.OrderExpr("?TableAlias.? DESC", column(q.QueryBuilder(), col.Col))
+		} else {
+			q = q.OrderExpr("?TableAlias.? ASC", column(q.QueryBuilder(), col.Col))
+		}
+	}
+
+	return q.Limit(k.limit() + 1)
+}
+
+// keysetExpression returns the condition selecting rows after the values in the columns order,
+// or before them if backward. Row-value comparison is used if all columns have the same direction,
+// otherwise it is expanded into (a > ?) OR (a = ? AND b < ?) ...
+func keysetExpression(cols []SortColumn, values []any, backward bool) (string, []any) {
+	ops := make([]string, len(cols))
+	mixed := false
+	for i, col := range cols {
+		ops[i] = ">"
+		if col.Desc != backward {
+			ops[i] = "<"
+		}
+		mixed = mixed || ops[i] != ops[0]
+	}
+
+	if !mixed {
+		args := make([]any, 0, len(cols)*2)
+		for _, col := range cols {
+			args = append(args, bun.Ident(col.Col))
+		}
+		args = append(args, values...)
+
+		placeholders := strings.Repeat(", ?", len(cols))[2:]
+		columns := strings.Repeat(", ?TableAlias.?", len(cols))[2:]
+		return "(" + columns + ") " + ops[0] + " (" + placeholders + ")", args
+	}
+
+	var (
+		terms []string
+		args  []any
+	)
+	for i := range cols {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, "?TableAlias.? = ?")
+			args = append(args, bun.Ident(cols[j].Col), values[j])
+		}
+		parts = append(parts, "?TableAlias.? "+ops[i]+" ?")
+		args = append(args, bun.Ident(cols[i].Col), values[i])
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+	return strings.Join(terms, " OR "), args
+}
+
+// EncodeCursor encodes the cursor as an opaque URL safe string, signed if the Keyset has Secret.
+func (k *Keyset) EncodeCursor(c Cursor) (string, error) {
+	c.Sort = k.sortKey()
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("bunutils: encode cursor: %w", err)
+	}
+
+	s := base64.RawURLEncoding.EncodeToString(b)
+	if len(k.Secret) > 0 {
+		s += "." + base64.RawURLEncoding.EncodeToString(k.sign(s))
+	}
+	return s, nil
+}
+
+// DecodeCursor decodes the cursor encoded with EncodeCursor and checks that it matches the sort columns
+// and their directions.
+func (k *Keyset) DecodeCursor(s string) (Cursor, error) {
+	payload := s
+	if len(k.Secret) > 0 {
+		var sig string
+		var ok bool
+		if payload, sig, ok = strings.Cut(s, "."); !ok {
+			return Cursor{}, fmt.Errorf("%w: missing signature", ErrInvalidCursor)
+		}
+		b, err := base64.RawURLEncoding.DecodeString(sig)
+		if err != nil || !hmac.Equal(b, k.sign(payload)) {
+			return Cursor{}, fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+		}
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var c Cursor
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if c.Sort != k.sortKey() {
+		return Cursor{}, fmt.Errorf("%w: sort columns mismatch", ErrInvalidCursor)
+	}
+	if len(c.Values) != len(k.columns()) {
+		return Cursor{}, fmt.Errorf("%w: expected %d values, got %d", ErrInvalidCursor, len(k.columns()), len(c.Values))
+	}
+
+	for i, v := range c.Values {
+		if n, ok := v.(json.Number); ok {
+			if c.Values[i], err = n.Int64(); err != nil {
+				c.Values[i], _ = n.Float64()
+			}
+		}
+	}
+	return c, nil
+}
+
+func (k *Keyset) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, k.Secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// KeysetCursors trims the extra row selected by Keyset.Select and returns the page items in the Keyset order
+// with the cursors of the next and previous pages, empty if there are no such pages.
+// Items are structs or pointers to structs of the queried model.
+func KeysetCursors[T any](db bun.IDB, k *Keyset, items []T) (page []T, next, prev string, err error) {
+	var cursor Cursor
+	if k.Cursor != "" {
+		if cursor, err = k.DecodeCursor(k.Cursor); err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	more := len(items) > k.limit()
+	if more {
+		items = items[:k.limit()]
+	}
+	if cursor.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if len(items) == 0 {
+		return items, "", "", nil
+	}
+
+	hasNext, hasPrev := more, k.Cursor != ""
+	if cursor.Backward {
+		hasNext, hasPrev = true, more
+	}
+
+	if hasNext {
+		if next, err = keysetItemCursor(db, k, items[len(items)-1], false); err != nil {
+			return nil, "", "", err
+		}
+	}
+	if hasPrev {
+		if prev, err = keysetItemCursor(db, k, items[0], true); err != nil {
+			return nil, "", "", err
+		}
+	}
+	return items, next, prev, nil
+}
+
+func keysetItemCursor(db bun.IDB, k *Keyset, item any, backward bool) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("bunutils: keyset item must be a struct, got %T", item)
+	}
+
+	table := db.Dialect().Tables().Get(v.Type())
+	cols := k.columns()
+	values := make([]any, len(cols))
+	for i, col := range cols {
+		field, err := table.Field(col.Col)
+		if err != nil {
+			return "", fmt.Errorf("bunutils: keyset: %w", err)
+		}
+		// NULL values can't be compared, the next page would be empty.
+		fv := field.Value(v)
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			return "", fmt.Errorf("bunutils: keyset column %q is NULL, sort columns must be NOT NULL", col.Col)
+		}
+
+		value := fv.Interface()
+		if valuer, ok := value.(driver.Valuer); ok {
+			if value, err = valuer.Value(); err != nil {
+				return "", fmt.Errorf("bunutils: keyset column %q: %w", col.Col, err)
+			}
+			if value == nil {
+				return "", fmt.Errorf("bunutils: keyset column %q is NULL, sort columns must be NOT NULL", col.Col)
+			}
+		}
+		values[i] = value
+	}
+
+	return k.EncodeCursor(Cursor{Values: values, Backward: backward})
+}
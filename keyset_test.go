@@ -0,0 +1,294 @@
+package bunutils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type keysetModel struct {
+	bun.BaseModel `bun:"table:items"`
+	ID            int64     `bun:"id,pk"`
+	Name          string    `bun:"name"`
+	CreatedAt     time.Time `bun:"created_at"`
+}
+
+func TestKeysetExpression(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		cols     []SortColumn
+		backward bool
+		want     string
+	}{
+		{
+			name: "ascending",
+			cols: []SortColumn{Asc("name"), Asc("id")},
+			want: `("keyset_model"."name", "keyset_model"."id") > ('a', 1)`,
+		},
+		{
+			name: "descending",
+			cols: []SortColumn{Desc("name"), Desc("id")},
+			want: `("keyset_model"."name", "keyset_model"."id") < ('a', 1)`,
+		},
+		{
+			name:     "backward",
+			cols:     []SortColumn{Desc("name"), Desc("id")},
+			backward: true,
+			want:     `("keyset_model"."name", "keyset_model"."id") > ('a', 1)`,
+		},
+		{
+			name: "mixed",
+			cols: []SortColumn{Desc("name"), Asc("id")},
+			want: `("keyset_model"."name" < 'a') OR ("keyset_model"."name" = 'a' AND "keyset_model"."id" > 1)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, args := keysetExpression(tt.cols, []any{"a", 1}, tt.backward)
+			sql := db.NewSelect().Model((*keysetModel)(nil)).Where(expr, args...).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetSelect(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	t.Run("first page", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Desc("created_at")}, Limit: 10}
+		sql := ks.Select(db.NewSelect().Model((*keysetModel)(nil))).String()
+
+		want := `ORDER BY "keyset_model"."created_at" DESC, "keyset_model"."id" DESC LIMIT 11`
+		if !strings.Contains(sql, want) || strings.Contains(sql, "WHERE") {
+			t.Errorf("got %s, want it to contain %s", sql, want)
+		}
+	})
+
+	t.Run("next page", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Asc("name")}}
+		ks.Cursor, _ = ks.EncodeCursor(Cursor{Values: []any{"bob", 7}})
+		sql := ks.Select(db.NewSelect().Model((*keysetModel)(nil))).String()
+
+		want := `WHERE (("keyset_model"."name", "keyset_model"."id") > ('bob', 7)) ORDER BY "keyset_model"."name" ASC, "keyset_model"."id" ASC LIMIT 21`
+		if !strings.Contains(sql, want) {
+			t.Errorf("got %s, want it to contain %s", sql, want)
+		}
+	})
+
+	t.Run("prev page", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Asc("name")}}
+		ks.Cursor, _ = ks.EncodeCursor(Cursor{Values: []any{"bob", 7}, Backward: true})
+		sql := ks.Select(db.NewSelect().Model((*keysetModel)(nil))).String()
+
+		want := `WHERE (("keyset_model"."name", "keyset_model"."id") < ('bob', 7)) ORDER BY "keyset_model"."name" DESC, "keyset_model"."id" DESC`
+		if !strings.Contains(sql, want) {
+			t.Errorf("got %s, want it to contain %s", sql, want)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		ks := &Keyset{Cursor: "!!!"}
+		result := ks.Select(db.NewSelect().Model((*keysetModel)(nil)))
+		if _, err := result.AppendQuery(db.Formatter(), nil); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Select() error = %v, want ErrInvalidCursor", err)
+		}
+	})
+}
+
+func TestKeysetCursor(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Asc("name")}}
+		s, err := ks.EncodeCursor(Cursor{Values: []any{"bob", int64(1) << 60}, Backward: true})
+		if err != nil {
+			t.Fatalf("EncodeCursor() error = %v", err)
+		}
+
+		c, err := ks.DecodeCursor(s)
+		if err != nil {
+			t.Fatalf("DecodeCursor() error = %v", err)
+		}
+		if !c.Backward || c.Values[0] != "bob" || c.Values[1] != int64(1)<<60 {
+			t.Errorf("DecodeCursor() = %+v", c)
+		}
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		ks := &Keyset{Secret: []byte("secret")}
+		s, _ := ks.EncodeCursor(Cursor{Values: []any{1}})
+
+		if _, err := ks.DecodeCursor(s); err != nil {
+			t.Errorf("DecodeCursor() error = %v", err)
+		}
+
+		other := &Keyset{Secret: []byte("other")}
+		if _, err := other.DecodeCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor() should fail with other secret, got %v", err)
+		}
+
+		unsigned, _ := (&Keyset{}).EncodeCursor(Cursor{Values: []any{1}})
+		if _, err := ks.DecodeCursor(unsigned); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor() should fail without signature, got %v", err)
+		}
+	})
+
+	t.Run("columns mismatch", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Asc("name")}}
+		s, _ := ks.EncodeCursor(Cursor{Values: []any{1}})
+		if _, err := ks.DecodeCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor() should fail with wrong number of values, got %v", err)
+		}
+	})
+
+	t.Run("sort mismatch", func(t *testing.T) {
+		byCreated := &Keyset{Columns: []SortColumn{Asc("created_at")}, Secret: []byte("secret")}
+		s, _ := byCreated.EncodeCursor(Cursor{Values: []any{"2025-01-01", 1}})
+
+		for _, ks := range []*Keyset{
+			{Columns: []SortColumn{Asc("name")}, Secret: []byte("secret")},
+			{Columns: []SortColumn{Desc("created_at")}, Secret: []byte("secret")},
+		} {
+			if _, err := ks.DecodeCursor(s); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() with %v should fail, got %v", ks.Columns, err)
+			}
+		}
+	})
+}
+
+func TestKeysetCursors_Null(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	type nullableModel struct {
+		bun.BaseModel `bun:"table:items"`
+		ID            int64   `bun:"id,pk"`
+		Name          *string `bun:"name"`
+	}
+
+	ks := &Keyset{Columns: []SortColumn{Asc("name")}, Limit: 1}
+	items := []*nullableModel{{ID: 1}, {ID: 2}}
+	if _, _, _, err := KeysetCursors(db, ks, items); err == nil {
+		t.Error("KeysetCursors() should fail with NULL sort column value")
+	}
+}
+
+func TestKeysetCursors(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	items := []*keysetModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+
+	t.Run("first page", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Asc("name")}, Limit: 2}
+		page, next, prev, err := KeysetCursors(db, ks, items)
+		if err != nil {
+			t.Fatalf("KeysetCursors() error = %v", err)
+		}
+		if len(page) != 2 || next == "" || prev != "" {
+			t.Fatalf("KeysetCursors() = %d items, next %q, prev %q", len(page), next, prev)
+		}
+
+		c, _ := ks.DecodeCursor(next)
+		if c.Backward || c.Values[0] != "b" || c.Values[1] != int64(2) {
+			t.Errorf("next cursor = %+v, want values of the last item", c)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Asc("name")}, Limit: 5}
+		ks.Cursor, _ = ks.EncodeCursor(Cursor{Values: []any{"0", 0}})
+		_, next, prev, err := KeysetCursors(db, ks, items)
+		if err != nil {
+			t.Fatalf("KeysetCursors() error = %v", err)
+		}
+		if next != "" || prev == "" {
+			t.Errorf("KeysetCursors() next %q, prev %q, want only prev", next, prev)
+		}
+
+		c, _ := ks.DecodeCursor(prev)
+		if !c.Backward || c.Values[0] != "a" {
+			t.Errorf("prev cursor = %+v, want backward values of the first item", c)
+		}
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		ks := &Keyset{Columns: []SortColumn{Asc("name")}, Limit: 2}
+		ks.Cursor, _ = ks.EncodeCursor(Cursor{Values: []any{"d", 4}, Backward: true})
+		reversed := []*keysetModel{items[2], items[1], items[0]}
+
+		page, next, prev, err := KeysetCursors(db, ks, reversed)
+		if err != nil {
+			t.Fatalf("KeysetCursors() error = %v", err)
+		}
+		if len(page) != 2 || page[0].Name != "b" || page[1].Name != "c" {
+			t.Errorf("KeysetCursors() should restore the order, got %v, %v", page[0].Name, page[1].Name)
+		}
+		if next == "" || prev == "" {
+			t.Errorf("KeysetCursors() next %q, prev %q, want both", next, prev)
+		}
+	})
+}
+
+func TestWhereKeyset(t *testing.T) {
+	where := &Where{
+		Limit:    ToPtr(5),
+		Cursor:   "cursor",
+		SortBy:   1,
+		SortDesc: true,
+		Order:    Order{1: "created_at"},
+	}
+
+	ks := where.Keyset(nil, nil)
+	if ks.Limit != 5 || ks.Cursor != "cursor" || len(ks.Columns) != 1 || ks.Columns[0] != Desc("created_at") {
+		t.Errorf("Keyset() = %+v", ks)
+	}
+}
+
+func TestWhereKeyset_Defaults(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	type keysetDefaultsModel struct {
+		bun.BaseModel `bun:"table:keyset_defaults"`
+		UUID          string `bun:"uuid,pk"`
+		Created       string `bun:"created"`
+	}
+	type keysetPKModel struct {
+		bun.BaseModel `bun:"table:keyset_pks"`
+		Key           string `bun:"key,pk"`
+	}
+
+	RegisterWhereDefaults((*keysetDefaultsModel)(nil), Where{
+		IDCol:        "uuid",
+		Order:        Order{1: "created"},
+		DefaultSorts: []Sort{{By: 1, Desc: true}},
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		q := db.NewSelect().Model((*keysetDefaultsModel)(nil))
+		ks := (&Where{}).Keyset(q, nil)
+
+		sql := q.Apply(ks.Select).String()
+		want := `ORDER BY "keyset_defaults_model"."created" DESC, "keyset_defaults_model"."uuid" DESC`
+		if !strings.Contains(sql, want) {
+			t.Errorf("got %s, want it to contain %s", sql, want)
+		}
+	})
+
+	t.Run("primary key", func(t *testing.T) {
+		q := db.NewSelect().Model((*keysetPKModel)(nil))
+		ks := (&Where{}).Keyset(q, nil)
+		if ks.IDCol != "key" {
+			t.Errorf("Keyset() IDCol = %q, want the primary key", ks.IDCol)
+		}
+	})
+}
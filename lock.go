@@ -0,0 +1,139 @@
+package bunutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// ErrLockOutsideTx is the query error of row locking selectors used without a transaction in the context.
+// The locks would be released as soon as the query completes.
+var ErrLockOutsideTx = errors.New("bunutils: row lock outside of transaction")
+
+type lockOptions struct {
+	noWait     bool
+	skipLocked bool
+	of         []string
+}
+
+// LockOption configures row locking selectors.
+type LockOption func(*lockOptions)
+
+// WithNoWait fails the query instead of waiting for rows locked by other transactions.
+func WithNoWait() LockOption {
+	return func(o *lockOptions) {
+		o.noWait = true
+	}
+}
+
+// WithSkipLocked skips rows locked by other transactions, e.g. to pick jobs from a queue table.
+func WithSkipLocked() LockOption {
+	return func(o *lockOptions) {
+		o.skipLocked = true
+	}
+}
+
+// WithLockOf locks only rows of the tables with the aliases, e.g. not the rows of joined tables.
+func WithLockOf(aliases ...string) LockOption {
+	return func(o *lockOptions) {
+		o.of = append(o.of, aliases...)
+	}
+}
+
+func newLockOptions(opts []LockOption) lockOptions {
+	var o lockOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// ForUpdate locks the selected rows with FOR UPDATE until the end of the transaction of ctx:
+//
+//	err := bunutils.InTx(ctx, db, func(ctx context.Context) error {
+//	    q := querier.NewSelectQuery(ctx).Model(&jobs).Limit(10).
+//	        Apply(bunutils.ForUpdate(ctx, bunutils.WithSkipLocked()))
+//	    ...
+//	})
+//
+// ErrLockOutsideTx is set as the query error if ctx has no transaction of TxToContext. bun doesn't expose
+// the connection of a query, so the query is bound to the transaction of ctx with Conn, also when it was
+// built with db.NewSelect(). Build it from the transaction anyway, e.g. with Querier, to make it obvious.
+//
+// Row locks are supported on PostgreSQL and MySQL, other dialects fail the query. FOR SHARE, OF, NOWAIT
+// and SKIP LOCKED require MySQL 8.0, older versions fail when the query is executed.
+func ForUpdate(ctx context.Context, opts ...LockOption) Selector {
+	return lockSelector(ctx, "UPDATE", opts)
+}
+
+// ForNoKeyUpdate locks the selected rows with FOR NO KEY UPDATE, which doesn't block FOR KEY SHARE locks
+// of foreign key checks. It is supported on PostgreSQL only. See ForUpdate.
+func ForNoKeyUpdate(ctx context.Context, opts ...LockOption) Selector {
+	return lockSelector(ctx, "NO KEY UPDATE", opts)
+}
+
+// ForShare locks the selected rows with FOR SHARE, which blocks updates but not other FOR SHARE locks.
+// See ForUpdate.
+func ForShare(ctx context.Context, opts ...LockOption) Selector {
+	return lockSelector(ctx, "SHARE", opts)
+}
+
+// ForKeyShare locks the selected rows with FOR KEY SHARE, which blocks only deletes and key updates.
+// It is supported on PostgreSQL only. See ForUpdate.
+func ForKeyShare(ctx context.Context, opts ...LockOption) Selector {
+	return lockSelector(ctx, "KEY SHARE", opts)
+}
+
+func lockSelector(ctx context.Context, strength string, opts []LockOption) Selector {
+	o := newLockOptions(opts)
+
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		tx := TxFromContext(ctx)
+		if tx == nil {
+			return q.Err(ErrLockOutsideTx)
+		}
+		if err := checkLock(q.Dialect().Name(), strength, o); err != nil {
+			return q.Err(err)
+		}
+
+		clause := strength
+		var args []any
+		if len(o.of) > 0 {
+			clause += " OF ?" + strings.Repeat(", ?", len(o.of)-1)
+			for _, alias := range o.of {
+				args = append(args, bun.Ident(alias))
+			}
+		}
+		switch {
+		case o.noWait:
+			clause += " NOWAIT"
+		case o.skipLocked:
+			clause += " SKIP LOCKED"
+		}
+		return q.Conn(tx).For(clause, args...)
+	}
+}
+
+// checkLock checks the lock strength and options with the dialect. The MySQL version isn't known,
+// MySQL 8.0 is assumed.
+func checkLock(name dialect.Name, strength string, o lockOptions) error {
+	if o.noWait && o.skipLocked {
+		return errors.New("bunutils: NOWAIT and SKIP LOCKED can't be used together")
+	}
+
+	switch name {
+	case dialect.PG:
+		return nil
+	case dialect.MySQL:
+		if strength == "UPDATE" || strength == "SHARE" {
+			return nil
+		}
+	}
+	return fmt.Errorf("bunutils: FOR %s is not supported by %s", strength, name)
+}
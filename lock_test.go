@@ -0,0 +1,98 @@
+package bunutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun/dialect"
+)
+
+func TestForUpdate(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	ctx := TxToContext(context.Background(), &tx)
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{name: "for update", selector: ForUpdate(ctx), want: `FOR UPDATE`},
+		{name: "no key update", selector: ForNoKeyUpdate(ctx, WithNoWait()), want: `FOR NO KEY UPDATE NOWAIT`},
+		{name: "share", selector: ForShare(ctx, WithSkipLocked()), want: `FOR SHARE SKIP LOCKED`},
+		{name: "key share", selector: ForKeyShare(ctx), want: `FOR KEY SHARE`},
+		{
+			name:     "of aliases",
+			selector: ForUpdate(ctx, WithLockOf("test_model", "o"), WithSkipLocked()),
+			want:     `FOR UPDATE OF "test_model", "o" SKIP LOCKED`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tx.NewSelect().Model((*testModel)(nil)).Apply(tt.selector).String()
+			if !strings.HasSuffix(sql, tt.want) {
+				t.Errorf("got %s, want it to end with %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("outside transaction", func(t *testing.T) {
+		q := db.NewSelect().Model((*testModel)(nil)).Apply(ForUpdate(context.Background()))
+		if _, err := q.AppendQuery(db.Formatter(), nil); !errors.Is(err, ErrLockOutsideTx) {
+			t.Errorf("ForUpdate() error = %v, want %v", err, ErrLockOutsideTx)
+		}
+	})
+
+	t.Run("runs on the context transaction", func(t *testing.T) {
+		done, _ := db.BeginTx(context.Background(), nil)
+		_ = done.Rollback()
+		ctx := TxToContext(context.Background(), &done)
+
+		var items []testModel
+		err := db.NewSelect().Model(&items).Apply(ForUpdate(ctx)).Scan(ctx)
+		if !errors.Is(err, sql.ErrTxDone) {
+			t.Errorf("ForUpdate() query error = %v, want it to run on the transaction", err)
+		}
+	})
+
+	t.Run("nowait and skip locked", func(t *testing.T) {
+		q := tx.NewSelect().Model((*testModel)(nil)).Apply(ForUpdate(ctx, WithNoWait(), WithSkipLocked()))
+		if _, err := q.AppendQuery(db.Formatter(), nil); err == nil {
+			t.Error("ForUpdate() should fail with both NOWAIT and SKIP LOCKED")
+		}
+	})
+}
+
+func TestCheckLock(t *testing.T) {
+	tests := []struct {
+		name     dialect.Name
+		strength string
+		wantErr  bool
+	}{
+		{name: dialect.PG, strength: "KEY SHARE"},
+		{name: dialect.MySQL, strength: "UPDATE"},
+		{name: dialect.MySQL, strength: "SHARE"},
+		{name: dialect.MySQL, strength: "NO KEY UPDATE", wantErr: true},
+		{name: dialect.SQLite, strength: "UPDATE", wantErr: true},
+		{name: dialect.MSSQL, strength: "SHARE", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name.String()+" "+tt.strength, func(t *testing.T) {
+			err := checkLock(tt.name, tt.strength, lockOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkLock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
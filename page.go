@@ -0,0 +1,220 @@
+package bunutils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/uptrace/bun"
+)
+
+// CountMode defines how ListPage counts the total number of rows.
+type CountMode int
+
+const (
+	// CountSeparate counts rows with bun.SelectQuery.ScanAndCount, a separate COUNT query
+	// running concurrently with the page query.
+	CountSeparate CountMode = iota
+	// CountWindow counts rows with count(*) OVER () column in the page query itself, in one round-trip.
+	// Columns are scanned with TableModel.ScanColumn, so relations of the query are not supported.
+	// If the page is empty, e.g. offset is beyond the last row, a COUNT query is used.
+	CountWindow
+)
+
+// windowTotalColumn is the column of CountWindow total.
+const windowTotalColumn = "__bunutils_total"
+
+// Page is a page of items with the total number of rows matching the filters.
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+}
+
+type pageOptions struct {
+	count CountMode
+}
+
+// PageOption configures ListPage.
+type PageOption func(*pageOptions)
+
+// WithCountMode sets how the total number of rows is counted, CountSeparate by default.
+func WithCountMode(mode CountMode) PageOption {
+	return func(o *pageOptions) {
+		o.count = mode
+	}
+}
+
+// PageWhere is the Where of ListPage, *WhereOf of any ID type, e.g. *Where or *WhereOf[int64].
+type PageWhere interface {
+	Where(q *bun.SelectQuery) *bun.SelectQuery
+	Select(q *bun.SelectQuery) *bun.SelectQuery
+	pagination() (limit, offset *int)
+}
+
+// ListPage selects a page of T with the total number of rows. The Where filters are applied
+// to both the page and the count, while its limit and offset only to the page:
+//
+//	page, err := bunutils.ListPage[User](ctx, db.NewSelect(), where)
+//
+// The query model is set to the items, so the query only needs filters which are not in the Where.
+// The where may be nil.
+func ListPage[T any](ctx context.Context, q *bun.SelectQuery, where PageWhere, opts ...PageOption) (Page[T], error) {
+	var o pageOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	page := Page[T]{Items: make([]T, 0)}
+	q = q.Model(&page.Items)
+	if where != nil {
+		limit, offset := where.pagination()
+		if limit != nil {
+			page.Limit = *limit
+		}
+		if offset != nil {
+			page.Offset = *offset
+		}
+		q = q.Apply(where.Where, where.Select)
+	}
+
+	var err error
+	switch o.count {
+	case CountWindow:
+		page.Items, page.Total, err = scanWindowPage[T](ctx, q)
+	default:
+		page.Total, err = q.ScanAndCount(ctx)
+	}
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	page.HasMore = page.Offset+len(page.Items) < page.Total
+	return page, nil
+}
+
+func scanWindowPage[T any](ctx context.Context, q *bun.SelectQuery) ([]T, int, error) {
+	// ExcludeColumn without columns keeps the selected columns or selects all the model columns.
+	rows, err := q.Clone().
+		ExcludeColumn().
+		ColumnExpr("count(*) OVER () AS ?", bun.Ident(windowTotalColumn)).
+		Rows(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	// The table model is built once and scans every row into the same item, which is copied to the page.
+	var item T
+	model, elem, err := itemTableModel(q.DB(), &item)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		items = make([]T, 0)
+		total int64
+	)
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, err
+		}
+
+		elem.SetZero()
+		if err := model.BeforeScanRow(ctx); err != nil {
+			return nil, 0, err
+		}
+		for i, column := range columns {
+			if column == windowTotalColumn {
+				if total, err = toInt64(values[i]); err != nil {
+					return nil, 0, err
+				}
+				continue
+			}
+			if err := model.ScanColumn(column, values[i]); err != nil {
+				return nil, 0, err
+			}
+		}
+		if err := model.AfterScanRow(ctx); err != nil {
+			return nil, 0, err
+		}
+
+		items = append(items, copyItem(item, elem))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if len(items) == 0 {
+		// Window count is not available without rows.
+		n, err := q.Count(ctx)
+		return items, n, err
+	}
+	return items, int(total), nil
+}
+
+// itemTableModel returns the table model scanning into the item and the struct it scans into,
+// allocating it if T is a pointer.
+func itemTableModel[T any](db *bun.DB, item *T) (bun.TableModel, reflect.Value, error) {
+	dest := any(item)
+	elem := reflect.ValueOf(item).Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem.Set(reflect.New(elem.Type().Elem()))
+		dest = elem.Interface()
+		elem = elem.Elem()
+	}
+
+	model, ok := db.NewSelect().Model(dest).GetModel().(bun.TableModel)
+	if !ok {
+		return nil, reflect.Value{}, fmt.Errorf("bunutils: %T is not a table model", *item)
+	}
+	return model, elem, nil
+}
+
+// copyItem returns a copy of the item scanned into elem, a new struct if T is a pointer.
+func copyItem[T any](item T, elem reflect.Value) T {
+	if reflect.TypeFor[T]().Kind() != reflect.Ptr {
+		return item
+	}
+	c := reflect.New(elem.Type())
+	c.Elem().Set(elem)
+	return c.Interface().(T)
+}
+
+func toInt64(value any) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	case []byte:
+		var n int64
+		_, err := fmt.Sscan(string(v), &n)
+		return n, err
+	case string:
+		var n int64
+		_, err := fmt.Sscan(v, &n)
+		return n, err
+	default:
+		return 0, fmt.Errorf("bunutils: unexpected count type %T", value)
+	}
+}
@@ -0,0 +1,208 @@
+package bunutils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// queryConnector is a driver returning rows depending on the query and recording the queries.
+type queryConnector struct {
+	mu      sync.Mutex
+	queries []string
+	rows    func(query string) ([]string, [][]driver.Value)
+}
+
+func (c *queryConnector) Connect(ctx context.Context) (driver.Conn, error) { return &queryConn{c}, nil }
+func (c *queryConnector) Driver() driver.Driver                            { return &mockDriver{} }
+
+func (c *queryConnector) executed() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.queries...)
+}
+
+type queryConn struct{ c *queryConnector }
+
+func (c *queryConn) Prepare(query string) (driver.Stmt, error) { return &queryStmt{c.c, query}, nil }
+func (c *queryConn) Close() error                              { return nil }
+func (c *queryConn) Begin() (driver.Tx, error)                 { return &mockTx{}, nil }
+
+type queryStmt struct {
+	c     *queryConnector
+	query string
+}
+
+func (s *queryStmt) Close() error  { return nil }
+func (s *queryStmt) NumInput() int { return -1 }
+func (s *queryStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return &mockResult{}, nil
+}
+
+func (s *queryStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.mu.Lock()
+	s.c.queries = append(s.c.queries, s.query)
+	s.c.mu.Unlock()
+
+	columns, rows := s.c.rows(s.query)
+	return &queryRows{columns: columns, rows: rows}, nil
+}
+
+type queryRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *queryRows) Columns() []string { return r.columns }
+func (r *queryRows) Close() error      { return nil }
+
+func (r *queryRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type pageModel struct {
+	bun.BaseModel `bun:"table:users"`
+	ID            int64  `bun:"id,pk"`
+	Name          string `bun:"name"`
+}
+
+func TestListPage(t *testing.T) {
+	connector := &queryConnector{rows: func(query string) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "OVER ()"):
+			return []string{"id", "name", windowTotalColumn}, [][]driver.Value{
+				{int64(3), "c", int64(5)},
+				{int64(4), "d", int64(5)},
+			}
+		case strings.Contains(query, "count(*)"):
+			return []string{"count"}, [][]driver.Value{{int64(5)}}
+		default:
+			return []string{"id", "name"}, [][]driver.Value{
+				{int64(3), "c"},
+				{int64(4), "d"},
+			}
+		}
+	}}
+	db := bun.NewDB(sql.OpenDB(connector), pgdialect.New())
+	defer db.Close()
+
+	where := &Where{Limit: ToPtr(2), Offset: ToPtr(2), SortBy: 1, Order: Order{1: "name"}}
+
+	tests := []struct {
+		name string
+		mode CountMode
+	}{
+		{name: "separate count", mode: CountSeparate},
+		{name: "window count", mode: CountWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := ListPage[*pageModel](context.Background(), db.NewSelect().Where("name <> ''"), where, WithCountMode(tt.mode))
+			if err != nil {
+				t.Fatalf("ListPage() error = %v", err)
+			}
+
+			if page.Total != 5 || page.Limit != 2 || page.Offset != 2 || !page.HasMore {
+				t.Errorf("ListPage() = %+v", page)
+			}
+			if len(page.Items) != 2 || page.Items[0].ID != 3 || page.Items[1].Name != "d" {
+				t.Errorf("ListPage() items = %v", page.Items)
+			}
+		})
+	}
+
+	queries := connector.executed()
+	for _, query := range queries {
+		if !strings.Contains(query, `WHERE (name <> '')`) {
+			t.Errorf("query should keep filters, got %s", query)
+		}
+		if strings.Contains(query, "count(*)") && !strings.Contains(query, "OVER ()") && strings.Contains(query, "LIMIT") {
+			t.Errorf("count query should ignore limit, got %s", query)
+		}
+	}
+}
+
+func TestListPage_Where(t *testing.T) {
+	connector := &queryConnector{rows: func(query string) ([]string, [][]driver.Value) {
+		if strings.Contains(query, "count(*)") {
+			return []string{"count"}, [][]driver.Value{{int64(1)}}
+		}
+		return []string{"id", "name"}, [][]driver.Value{{int64(3), "c"}}
+	}}
+	db := bun.NewDB(sql.OpenDB(connector), pgdialect.New())
+	defer db.Close()
+
+	t.Run("nil", func(t *testing.T) {
+		page, err := ListPage[*pageModel](context.Background(), db.NewSelect(), nil)
+		if err != nil || page.Total != 1 || len(page.Items) != 1 {
+			t.Errorf("ListPage() = %+v, %v", page, err)
+		}
+	})
+
+	t.Run("typed IDs", func(t *testing.T) {
+		where := &WhereOf[int64]{IDs: []int64{3}, Limit: ToPtr(10)}
+		page, err := ListPage[*pageModel](context.Background(), db.NewSelect(), where)
+		if err != nil || page.Limit != 10 {
+			t.Errorf("ListPage() = %+v, %v", page, err)
+		}
+
+		var nilWhere *WhereOf[int64]
+		if _, err := ListPage[*pageModel](context.Background(), db.NewSelect(), nilWhere); err != nil {
+			t.Errorf("ListPage() with nil *WhereOf error = %v", err)
+		}
+	})
+}
+
+func TestListPage_WindowRelation(t *testing.T) {
+	connector := &queryConnector{rows: func(query string) ([]string, [][]driver.Value) {
+		return []string{"id", "user_id", "status", "user__id", windowTotalColumn}, [][]driver.Value{
+			{int64(1), int64(10), "new", int64(10), int64(2)},
+			{int64(2), int64(20), "paid", int64(20), int64(2)},
+		}
+	}}
+	db := bun.NewDB(sql.OpenDB(connector), pgdialect.New())
+	defer db.Close()
+
+	check := func(t *testing.T, items []relOrder) {
+		t.Helper()
+		if len(items) != 2 || items[0].Status != "new" || items[1].Status != "paid" {
+			t.Fatalf("ListPage() items = %+v", items)
+		}
+		if items[0].User == nil || items[1].User == nil || items[0].User == items[1].User ||
+			items[0].User.ID != 10 || items[1].User.ID != 20 {
+			t.Errorf("ListPage() users = %+v, %+v", items[0].User, items[1].User)
+		}
+	}
+
+	t.Run("values", func(t *testing.T) {
+		page, err := ListPage[relOrder](context.Background(), db.NewSelect().Relation("User"), nil, WithCountMode(CountWindow))
+		if err != nil || page.Total != 2 {
+			t.Fatalf("ListPage() = %+v, %v", page, err)
+		}
+		check(t, page.Items)
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		page, err := ListPage[*relOrder](context.Background(), db.NewSelect().Relation("User"), nil, WithCountMode(CountWindow))
+		if err != nil || page.Total != 2 {
+			t.Fatalf("ListPage() = %+v, %v", page, err)
+		}
+		if page.Items[0] == page.Items[1] {
+			t.Fatal("ListPage() items share a pointer")
+		}
+		check(t, []relOrder{*page.Items[0], *page.Items[1]})
+	})
+}
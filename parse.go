@@ -0,0 +1,325 @@
+package bunutils
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseWhereOptions configures ParseWhere.
+type ParseWhereOptions struct {
+	// DefaultLimit is used if the limit is not set, no limit if zero. It is clamped to MaxLimit.
+	DefaultLimit int
+	// MaxLimit is the maximum limit, unlimited if zero. With MaxLimit a missing limit defaults to
+	// DefaultLimit or MaxLimit, so the result is always limited.
+	MaxLimit int
+	// Order is the whitelist of sort columns, sort_by and sorts must refer to its keys.
+	Order Order
+	// DefaultSorts are used if no sort is set, the keys of Order start at 1 then. See WhereOf.DefaultSorts.
+	DefaultSorts []Sort
+	// Columns is the whitelist of select_columns and exclude_columns, they are rejected if empty.
+	Columns []string
+}
+
+// FieldError is a validation error of a query string parameter.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors are field errors returned by ParseWhere.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "bunutils: invalid query: " + strings.Join(msgs, "; ")
+}
+
+// ParseWhere binds Where from HTTP query string parameters named as its form tags.
+// See ParseWhereOf.
+func ParseWhere(values url.Values, opts ParseWhereOptions) (*Where, error) {
+	return ParseWhereOf[string](values, opts)
+}
+
+// ParseWhereOf binds WhereOf from HTTP query string parameters named as its form tags:
+//
+//   - lists are accepted both comma separated and repeated, ids=1,2 and ids=1&ids=2;
+//   - timestamps are RFC3339 or unix milliseconds, created_after=2025-01-01T00:00:00Z;
+//   - booleans are strconv.ParseBool values, the flag without value is true, ?only_deleted;
+//   - sorts are comma separated Order keys with optional direction and NULLs placement,
+//     sorts=1,2:desc:nulls_last.
+//
+// IDs are parsed with encoding.TextUnmarshaler, e.g. uuid.UUID, or as strings and integers.
+// Column name settings are not bound, unknown parameters are ignored.
+// All invalid parameters are reported at once as ValidationErrors.
+func ParseWhereOf[ID comparable](values url.Values, opts ParseWhereOptions) (*WhereOf[ID], error) {
+	p := whereParser{values: values}
+	w := &WhereOf[ID]{Order: opts.Order, DefaultSorts: opts.DefaultSorts}
+
+	if s, ok := p.value("id"); ok && s != "" {
+		id, err := parseID[ID](s)
+		p.check("id", err)
+		w.ID = id
+	}
+	w.IDs = parseIDs[ID](&p, "ids")
+	w.NotInIDs = parseIDs[ID](&p, "not_in_ids")
+
+	w.HasFlags = p.ints("has_flags")
+	w.HasNotFlags = p.ints("has_not_flags")
+
+	w.OnlyDeleted = p.bool("only_deleted")
+	w.WithDeleted = p.bool("with_deleted")
+
+	w.Limit = p.int("limit")
+	w.Offset = p.int("offset")
+	w.Cursor, _ = p.value("cursor")
+
+	w.CreatedAfter = p.time("created_after")
+	w.CreatedBefore = p.time("created_before")
+	w.UpdatedAfter = p.time("updated_after")
+	w.UpdatedBefore = p.time("updated_before")
+
+	w.SelectColumns = p.columns("select_columns", opts.Columns)
+	w.ExcludeColumns = p.columns("exclude_columns", opts.Columns)
+
+	if sortBy := p.int("sort_by"); sortBy != nil {
+		if _, ok := opts.Order[*sortBy]; !ok {
+			p.fail("sort_by", "unknown sort key %d", *sortBy)
+		}
+		w.SortBy = *sortBy
+	}
+	w.SortDesc = p.bool("sort_desc")
+	w.Sorts = p.sorts("sorts", opts.Order)
+
+	switch {
+	case w.Limit != nil && *w.Limit < 1:
+		// bun leaves LIMIT 0 out of the query, so it would bypass MaxLimit.
+		p.fail("limit", "must be positive")
+	case w.Limit != nil && opts.MaxLimit > 0 && *w.Limit > opts.MaxLimit:
+		p.fail("limit", "must be at most %d", opts.MaxLimit)
+	case w.Limit == nil && opts.DefaultLimit > 0 && opts.MaxLimit > 0:
+		w.Limit = ToPtr(min(opts.DefaultLimit, opts.MaxLimit))
+	case w.Limit == nil && opts.DefaultLimit > 0:
+		w.Limit = ToPtr(opts.DefaultLimit)
+	case w.Limit == nil && opts.MaxLimit > 0:
+		w.Limit = ToPtr(opts.MaxLimit)
+	}
+	if w.Offset != nil && *w.Offset < 0 {
+		p.fail("offset", "must not be negative")
+	}
+
+	if len(p.errs) > 0 {
+		return nil, p.errs
+	}
+	return w, nil
+}
+
+type whereParser struct {
+	values url.Values
+	errs   ValidationErrors
+}
+
+func (p *whereParser) fail(field string, format string, args ...any) {
+	p.errs = append(p.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *whereParser) check(field string, err error) {
+	if err != nil {
+		p.fail(field, "%v", err)
+	}
+}
+
+// value returns the last value of the parameter.
+func (p *whereParser) value(field string) (string, bool) {
+	values, ok := p.values[field]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return strings.TrimSpace(values[len(values)-1]), true
+}
+
+// list returns comma separated and repeated values of the parameter.
+func (p *whereParser) list(field string) []string {
+	return splitList(p.values[field])
+}
+
+func (p *whereParser) bool(field string) bool {
+	s, ok := p.value(field)
+	if !ok {
+		return false
+	}
+	if s == "" {
+		return true
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		p.fail(field, "invalid boolean %q", s)
+	}
+	return b
+}
+
+func (p *whereParser) int(field string) *int {
+	s, ok := p.value(field)
+	if !ok || s == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		p.fail(field, "invalid integer %q", s)
+		return nil
+	}
+	return &n
+}
+
+func (p *whereParser) ints(field string) []int {
+	var ints []int
+	for _, s := range p.list(field) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			p.fail(field, "invalid integer %q", s)
+			continue
+		}
+		ints = append(ints, n)
+	}
+	return ints
+}
+
+func (p *whereParser) time(field string) *int64 {
+	s, ok := p.value(field)
+	if !ok || s == "" {
+		return nil
+	}
+	ms, err := parseTimestamp(s)
+	if err != nil {
+		p.fail(field, "invalid timestamp %q, expected RFC3339 or unix milliseconds", s)
+		return nil
+	}
+	return &ms
+}
+
+func (p *whereParser) columns(field string, allowed []string) []string {
+	columns := p.list(field)
+	for _, col := range columns {
+		if !slices.Contains(allowed, col) {
+			p.fail(field, "unknown column %q", col)
+		}
+	}
+	return columns
+}
+
+func (p *whereParser) sorts(field string, order Order) []Sort {
+	var sorts []Sort
+	seen := make(map[int]bool)
+	for _, s := range p.list(field) {
+		sort, err := parseSort(s)
+		if err != nil {
+			p.fail(field, "%v", err)
+			continue
+		}
+		if _, ok := order[sort.By]; !ok {
+			p.fail(field, "unknown sort key %d", sort.By)
+			continue
+		}
+		if seen[sort.By] {
+			p.fail(field, "duplicate sort key %d", sort.By)
+			continue
+		}
+		seen[sort.By] = true
+		sorts = append(sorts, sort)
+	}
+	return sorts
+}
+
+func parseIDs[ID comparable](p *whereParser, field string) []ID {
+	var ids []ID
+	for _, s := range p.list(field) {
+		id, err := parseID[ID](s)
+		if err != nil {
+			p.check(field, err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func parseID[ID comparable](s string) (ID, error) {
+	var id ID
+	if u, ok := any(&id).(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", s, err)
+		}
+		return id, nil
+	}
+
+	v := reflect.ValueOf(&id).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q", s)
+		}
+		v.SetUint(n)
+	default:
+		return id, fmt.Errorf("unsupported id type %T", id)
+	}
+	return id, nil
+}
+
+// parseTimestamp parses RFC3339 time or unix milliseconds into unix milliseconds.
+func parseTimestamp(s string) (int64, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ms, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
+
+// parseSort parses <key>[:asc|desc][:nulls_first|nulls_last].
+func parseSort(s string) (Sort, error) {
+	parts := strings.Split(s, ":")
+	by, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Sort{}, fmt.Errorf("invalid sort key %q", parts[0])
+	}
+
+	sort := Sort{By: by}
+	for _, part := range parts[1:] {
+		switch strings.ToLower(part) {
+		case "asc":
+			sort.Desc = false
+		case "desc":
+			sort.Desc = true
+		case "nulls_first":
+			sort.Nulls = NullsFirst
+		case "nulls_last":
+			sort.Nulls = NullsLast
+		default:
+			return Sort{}, fmt.Errorf("invalid sort option %q", part)
+		}
+	}
+	return sort, nil
+}
@@ -0,0 +1,157 @@
+package bunutils
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type hexID struct {
+	v uint64
+}
+
+func (id *hexID) UnmarshalText(b []byte) error {
+	v, err := strconv.ParseUint(string(b), 16, 64)
+	id.v = v
+	return err
+}
+
+func TestParseWhere(t *testing.T) {
+	opts := ParseWhereOptions{
+		DefaultLimit: 20,
+		MaxLimit:     100,
+		Order:        Order{1: "name", 2: "created_at"},
+		Columns:      []string{"id", "name"},
+	}
+
+	t.Run("lists", func(t *testing.T) {
+		values, _ := url.ParseQuery("ids=a,b&ids=c&not_in_ids=d&has_flags=1,2&has_not_flags=4")
+		where, err := ParseWhere(values, opts)
+		if err != nil {
+			t.Fatalf("ParseWhere() error = %v", err)
+		}
+
+		if !slices.Equal(where.IDs, []string{"a", "b", "c"}) || !slices.Equal(where.NotInIDs, []string{"d"}) {
+			t.Errorf("ParseWhere() ids = %v, not in ids = %v", where.IDs, where.NotInIDs)
+		}
+		if !slices.Equal(where.HasFlags, []int{1, 2}) || !slices.Equal(where.HasNotFlags, []int{4}) {
+			t.Errorf("ParseWhere() flags = %v, %v", where.HasFlags, where.HasNotFlags)
+		}
+	})
+
+	t.Run("timestamps", func(t *testing.T) {
+		values, _ := url.ParseQuery("created_after=2025-01-02T03:04:05Z&created_before=1735700000000")
+		where, err := ParseWhere(values, opts)
+		if err != nil {
+			t.Fatalf("ParseWhere() error = %v", err)
+		}
+
+		want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli()
+		if *where.CreatedAfter != want || *where.CreatedBefore != 1735700000000 {
+			t.Errorf("ParseWhere() created = %d, %d", *where.CreatedAfter, *where.CreatedBefore)
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		where, err := ParseWhere(url.Values{}, opts)
+		if err != nil {
+			t.Fatalf("ParseWhere() error = %v", err)
+		}
+		if where.Limit == nil || *where.Limit != 20 {
+			t.Errorf("ParseWhere() should set default limit, got %v", where.Limit)
+		}
+
+		where, _ = ParseWhere(url.Values{}, ParseWhereOptions{MaxLimit: 50})
+		if where.Limit == nil || *where.Limit != 50 {
+			t.Errorf("ParseWhere() should limit by max limit, got %v", where.Limit)
+		}
+
+		where, _ = ParseWhere(url.Values{}, ParseWhereOptions{DefaultLimit: 500, MaxLimit: 100})
+		if where.Limit == nil || *where.Limit != 100 {
+			t.Errorf("ParseWhere() should clamp default limit to max limit, got %v", where.Limit)
+		}
+
+		for _, limit := range []string{"0", "-1"} {
+			_, err := ParseWhere(url.Values{"limit": {limit}}, ParseWhereOptions{MaxLimit: 100})
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Field != "limit" {
+				t.Errorf("ParseWhere() limit=%s error = %v, want limit error", limit, err)
+			}
+		}
+	})
+
+	t.Run("zero sort key", func(t *testing.T) {
+		where, err := ParseWhere(url.Values{}, ParseWhereOptions{Order: Order{0: "name", 1: "created_at"}})
+		if err != nil {
+			t.Fatalf("ParseWhere() error = %v", err)
+		}
+		if sorts := where.sorts(); len(sorts) != 1 || sorts[0].By != 0 {
+			t.Errorf("ParseWhere() without sort_by should sort by the key 0 like a zero SortBy, got %v", sorts)
+		}
+
+		values, _ := url.ParseQuery("sort_by=0")
+		where, _ = ParseWhere(values, ParseWhereOptions{Order: Order{0: "name"}})
+		if sorts := where.sorts(); len(sorts) != 1 || sorts[0].By != 0 {
+			t.Errorf("ParseWhere() with sort_by=0 should sort by the key 0, got %v", sorts)
+		}
+	})
+
+	t.Run("sorting", func(t *testing.T) {
+		values, _ := url.ParseQuery("sort_by=2&sort_desc&sorts=1,2:desc:nulls_last&only_deleted=true")
+		where, err := ParseWhere(values, opts)
+		if err != nil {
+			t.Fatalf("ParseWhere() error = %v", err)
+		}
+
+		if where.SortBy != 2 || !where.SortDesc || !where.OnlyDeleted {
+			t.Errorf("ParseWhere() = %+v", where)
+		}
+		want := []Sort{{By: 1}, {By: 2, Desc: true, Nulls: NullsLast}}
+		if !slices.Equal(where.Sorts, want) {
+			t.Errorf("ParseWhere() sorts = %v, want %v", where.Sorts, want)
+		}
+	})
+
+	t.Run("typed IDs", func(t *testing.T) {
+		values, _ := url.ParseQuery("id=7&ids=1,2")
+		where, err := ParseWhereOf[int64](values, opts)
+		if err != nil {
+			t.Fatalf("ParseWhereOf() error = %v", err)
+		}
+		if where.ID != 7 || !slices.Equal(where.IDs, []int64{1, 2}) {
+			t.Errorf("ParseWhereOf() = %+v", where)
+		}
+
+		values, _ = url.ParseQuery("ids=ff")
+		hexWhere, err := ParseWhereOf[hexID](values, opts)
+		if err != nil {
+			t.Fatalf("ParseWhereOf() error = %v", err)
+		}
+		if len(hexWhere.IDs) != 1 || hexWhere.IDs[0].v != 255 {
+			t.Errorf("ParseWhereOf() should use UnmarshalText, got %+v", hexWhere.IDs)
+		}
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		values, _ := url.ParseQuery("ids=1,x&limit=500&offset=-1&created_after=yesterday&sort_by=9&sorts=1,1&select_columns=password&only_deleted=maybe")
+		_, err := ParseWhereOf[int64](values, opts)
+
+		var errs ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("ParseWhereOf() error = %v, want ValidationErrors", err)
+		}
+
+		fields := make([]string, len(errs))
+		for i, e := range errs {
+			fields[i] = e.Field
+		}
+		for _, field := range []string{"ids", "limit", "offset", "created_after", "sort_by", "sorts", "select_columns", "only_deleted"} {
+			if !slices.Contains(fields, field) {
+				t.Errorf("ParseWhereOf() should report %s, got %v", field, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package bunutils
+
+import (
+	"fmt"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// CondHas is the Condition counterpart of WhereHas.
+func CondHas(relation string, selectors ...Selector) Condition {
+	return relationExistsCondition("EXISTS", relation, selectors)
+}
+
+// CondDoesntHave is the Condition counterpart of WhereDoesntHave.
+func CondDoesntHave(relation string, selectors ...Selector) Condition {
+	return relationExistsCondition("NOT EXISTS", relation, selectors)
+}
+
+// WhereHas keeps rows which have at least one related row of the model relation,
+// e.g. users with orders. The relation is the name of the struct field with has-one, has-many,
+// belongs-to or m2m bun tag. Selectors apply to the related table, so ?TableAlias refers to it:
+//
+//	WhereHas("Orders", WhereEqual("status", "paid"))
+//	// EXISTS (SELECT 1 FROM "orders" AS "order" WHERE ("order"."user_id" = "user"."id") AND ("order"."status" = 'paid'))
+//
+// Nested relations are matched with nested selectors, e.g. WhereHas("Orders", WhereHas("Items")).
+// Self-referencing relations are not supported, as the related table has the same alias.
+func WhereHas(relation string, selectors ...Selector) Selector {
+	return CondHas(relation, selectors...).Select
+}
+
+// WhereDoesntHave keeps rows which have no related rows of the model relation matching the selectors.
+// See WhereHas.
+func WhereDoesntHave(relation string, selectors ...Selector) Selector {
+	return CondDoesntHave(relation, selectors...).Select
+}
+
+func relationExistsCondition(op string, relation string, selectors []Selector) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		sub, err := relationSubquery(q, relation)
+		if err != nil {
+			return setQueryErr(q, err)
+		}
+		sub = sub.Apply(Apply(selectors...))
+		// bun formats errors of subqueries into the SQL text, so they are reported on the query.
+		if _, err := sub.AppendQuery(sub.DB().Formatter(), nil); err != nil {
+			return setQueryErr(q, err)
+		}
+		return q.Where(op+" (?)", sub)
+	}
+}
+
+// relationSubquery builds SELECT 1 from the related table correlated with the base table of the query.
+func relationSubquery(q bun.QueryBuilder, relation string) (*bun.SelectQuery, error) {
+	query, ok := q.Unwrap().(interface {
+		DB() *bun.DB
+		GetModel() bun.Model
+	})
+	if !ok {
+		return nil, fmt.Errorf("bunutils: relation %q: unsupported query", relation)
+	}
+	model, ok := query.GetModel().(bun.TableModel)
+	if !ok {
+		return nil, fmt.Errorf("bunutils: relation %q: query has no table model", relation)
+	}
+
+	table := model.Table()
+	rel, ok := table.Relations[relation]
+	if !ok {
+		return nil, fmt.Errorf("bunutils: %s does not have relation %q", table.TypeName, relation)
+	}
+	if rel.JoinTable.SQLAlias == table.SQLAlias {
+		return nil, fmt.Errorf("bunutils: relation %q: self-referencing relations are not supported", relation)
+	}
+
+	sub := query.DB().NewSelect().
+		Model(rel.JoinTable.ZeroIface).
+		ColumnExpr("1")
+
+	switch rel.Type {
+	case schema.HasOneRelation, schema.HasManyRelation, schema.BelongsToRelation:
+		for i, base := range rel.BasePKs {
+			sub = sub.Where("?.? = ?.?",
+				rel.JoinTable.SQLAlias, rel.JoinPKs[i].SQLName,
+				table.SQLAlias, base.SQLName)
+		}
+		if rel.PolymorphicField != nil {
+			sub = sub.Where("?.? = ?", rel.JoinTable.SQLAlias, rel.PolymorphicField.SQLName, rel.PolymorphicValue)
+		}
+	case schema.ManyToManyRelation:
+		if rel.M2MTable == nil {
+			return nil, fmt.Errorf("bunutils: relation %q: m2m model is not registered", relation)
+		}
+		m2m := rel.M2MTable
+		sub = sub.Join("JOIN ? AS ?", m2m.SQLName, m2m.SQLAlias)
+		for i, join := range rel.JoinPKs {
+			sub = sub.JoinOn("?.? = ?.?",
+				m2m.SQLAlias, rel.M2MJoinPKs[i].SQLName,
+				rel.JoinTable.SQLAlias, join.SQLName)
+		}
+		for i, base := range rel.BasePKs {
+			sub = sub.Where("?.? = ?.?",
+				m2m.SQLAlias, rel.M2MBasePKs[i].SQLName,
+				table.SQLAlias, base.SQLName)
+		}
+	default:
+		return nil, fmt.Errorf("bunutils: relation %q: unsupported relation type", relation)
+	}
+
+	for _, cond := range rel.Condition {
+		sub = sub.Where(cond)
+	}
+	return sub, nil
+}
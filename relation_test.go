@@ -0,0 +1,117 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type relUser struct {
+	bun.BaseModel `bun:"table:users,alias:u"`
+	ID            int64       `bun:"id,pk"`
+	Orders        []*relOrder `bun:"rel:has-many,join:id=user_id"`
+	Groups        []*relGroup `bun:"m2m:user_groups,join:User=Group"`
+}
+
+type relOrder struct {
+	bun.BaseModel `bun:"table:orders,alias:o"`
+	ID            int64    `bun:"id,pk"`
+	UserID        int64    `bun:"user_id"`
+	Status        string   `bun:"status"`
+	User          *relUser `bun:"rel:belongs-to,join:user_id=id"`
+}
+
+type relGroup struct {
+	bun.BaseModel `bun:"table:groups,alias:g"`
+	ID            int64  `bun:"id,pk"`
+	Name          string `bun:"name"`
+}
+
+type relUserGroup struct {
+	bun.BaseModel `bun:"table:user_groups,alias:ug"`
+	UserID        int64     `bun:"user_id,pk"`
+	User          *relUser  `bun:"rel:belongs-to,join:user_id=id"`
+	GroupID       int64     `bun:"group_id,pk"`
+	Group         *relGroup `bun:"rel:belongs-to,join:group_id=id"`
+}
+
+func TestWhereHas(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+	db.RegisterModel((*relUserGroup)(nil))
+
+	tests := []struct {
+		name     string
+		query    *bun.SelectQuery
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "has many",
+			query:    db.NewSelect().Model((*relUser)(nil)),
+			selector: WhereHas("Orders", WhereEqual("status", "paid")),
+			want:     `WHERE (EXISTS (SELECT 1 FROM "orders" AS "o" WHERE ("o"."user_id" = "u"."id") AND ("o"."status" = 'paid')))`,
+		},
+		{
+			name:     "belongs to",
+			query:    db.NewSelect().Model((*relOrder)(nil)),
+			selector: WhereHas("User"),
+			want:     `WHERE (EXISTS (SELECT 1 FROM "users" AS "u" WHERE ("u"."id" = "o"."user_id")))`,
+		},
+		{
+			name:     "m2m",
+			query:    db.NewSelect().Model((*relUser)(nil)),
+			selector: WhereHas("Groups", WhereEqual("name", "admins")),
+			want:     `WHERE (EXISTS (SELECT 1 FROM "groups" AS "g" JOIN "user_groups" AS "ug" ON ("ug"."group_id" = "g"."id") WHERE ("ug"."user_id" = "u"."id") AND ("g"."name" = 'admins')))`,
+		},
+		{
+			name:     "doesnt have",
+			query:    db.NewSelect().Model((*relUser)(nil)),
+			selector: WhereDoesntHave("Orders"),
+			want:     `WHERE (NOT EXISTS (SELECT 1 FROM "orders" AS "o" WHERE ("o"."user_id" = "u"."id")))`,
+		},
+		{
+			name:     "nested",
+			query:    db.NewSelect().Model((*relOrder)(nil)),
+			selector: WhereHas("User", WhereHas("Groups")),
+			want:     `WHERE (EXISTS (SELECT 1 FROM "users" AS "u" WHERE ("u"."id" = "o"."user_id") AND (EXISTS (SELECT 1 FROM "groups" AS "g" JOIN "user_groups" AS "ug" ON ("ug"."group_id" = "g"."id") WHERE ("ug"."user_id" = "u"."id")))))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(tt.query).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown relation", func(t *testing.T) {
+		result := WhereHas("Invoices")(db.NewSelect().Model((*relUser)(nil)))
+		if _, err := result.AppendQuery(db.Formatter(), nil); err == nil {
+			t.Error("WhereHas() should fail with unknown relation")
+		}
+	})
+
+	t.Run("unknown nested relation", func(t *testing.T) {
+		result := WhereHas("Orders", WhereHas("Invoices"))(db.NewSelect().Model((*relUser)(nil)))
+		if _, err := result.AppendQuery(db.Formatter(), nil); err == nil {
+			t.Error("WhereHas() should report errors of nested selectors")
+		}
+	})
+}
+
+func TestCondHas(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+	db.RegisterModel((*relUserGroup)(nil))
+
+	query := db.NewDelete().Model((*relUser)(nil))
+	sql := CondDoesntHave("Orders").Delete(query).String()
+
+	if !strings.Contains(sql, `WHERE (NOT EXISTS (SELECT 1 FROM "orders" AS "o" WHERE ("o"."user_id" = "u"."id")))`) {
+		t.Errorf("CondDoesntHave() should add condition to DELETE, got %s", sql)
+	}
+}
@@ -0,0 +1,462 @@
+package bunutils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultRSQLMaxDepth is the maximum nesting depth of parentheses in RSQL expressions.
+	DefaultRSQLMaxDepth = 5
+	// DefaultRSQLMaxTerms is the maximum number of comparisons in RSQL expressions.
+	DefaultRSQLMaxTerms = 50
+	// DefaultRSQLMaxListItems is the maximum number of arguments of a comparison, e.g. of =in=.
+	DefaultRSQLMaxListItems = 100
+)
+
+// RSQLOptions limits RSQL expressions, defaults are used for zero values.
+type RSQLOptions struct {
+	MaxDepth     int
+	MaxTerms     int
+	MaxListItems int
+}
+
+// RSQLError is a syntax or validation error of RSQL expression.
+type RSQLError struct {
+	// Pos is the byte offset of the error in the expression.
+	Pos int
+	Msg string
+}
+
+func (e *RSQLError) Error() string {
+	return fmt.Sprintf("bunutils: rsql: %s at offset %d", e.Msg, e.Pos)
+}
+
+// RSQLNode is a node of RSQL expression: *RSQLLogical or *RSQLComparison.
+type RSQLNode interface {
+	// Position returns the byte offset of the node in the expression.
+	Position() int
+	// Selector compiles the node into a Selector with the fields of the schema.
+	Selector(schema FilterSchema) (Selector, error)
+	// Condition is the Condition counterpart of Selector.
+	Condition(schema FilterSchema) (Condition, error)
+}
+
+// RSQLLogicalOp is a logical operator of RSQL: ";" is AND, "," is OR.
+type RSQLLogicalOp string
+
+const (
+	RSQLAnd RSQLLogicalOp = ";"
+	RSQLOr  RSQLLogicalOp = ","
+)
+
+// RSQLLogical is a list of nodes joined by the same logical operator.
+type RSQLLogical struct {
+	Op    RSQLLogicalOp
+	Nodes []RSQLNode
+	Pos   int
+}
+
+func (n *RSQLLogical) Position() int {
+	return n.Pos
+}
+
+// Selector compiles AND into AndGroup and OR into Or of the node Selectors.
+func (n *RSQLLogical) Selector(schema FilterSchema) (Selector, error) {
+	cond, err := n.Condition(schema)
+	if err != nil {
+		return nil, err
+	}
+	return cond.Select, nil
+}
+
+// Condition compiles AND into CondAndGroup and OR into CondOr of the node Conditions.
+func (n *RSQLLogical) Condition(schema FilterSchema) (Condition, error) {
+	conds := make([]Condition, len(n.Nodes))
+	for i, node := range n.Nodes {
+		cond, err := node.Condition(schema)
+		if err != nil {
+			return nil, err
+		}
+		conds[i] = cond
+	}
+
+	if n.Op == RSQLOr {
+		return CondOr(conds...), nil
+	}
+	return CondAndGroup(conds...), nil
+}
+
+// RSQLComparison is a comparison of a field with arguments, e.g. age=ge=18 or role=in=(admin,owner).
+type RSQLComparison struct {
+	Field string
+	Op    FilterOp
+	Args  []string
+	Pos   int
+}
+
+func (n *RSQLComparison) Position() int {
+	return n.Pos
+}
+
+// Selector compiles the comparison with FilterSchema.Selector.
+func (n *RSQLComparison) Selector(schema FilterSchema) (Selector, error) {
+	cond, err := n.Condition(schema)
+	if err != nil {
+		return nil, err
+	}
+	return cond.Select, nil
+}
+
+// Condition compiles the comparison with FilterSchema.Condition.
+func (n *RSQLComparison) Condition(schema FilterSchema) (Condition, error) {
+	cond, err := schema.Condition(n.Field, n.Op, n.Args...)
+	if err != nil {
+		return nil, &RSQLError{Pos: n.Pos, Msg: err.Error()}
+	}
+	return cond, nil
+}
+
+// rsqlOperators maps RSQL/FIQL comparison operators to filter operators.
+// Other =name= operators are filter operators by name, e.g. =contains=.
+var rsqlOperators = map[string]FilterOp{
+	"==":    FilterEq,
+	"!=":    FilterNe,
+	"=gt=":  FilterGt,
+	">":     FilterGt,
+	"=ge=":  FilterGte,
+	">=":    FilterGte,
+	"=lt=":  FilterLt,
+	"<":     FilterLt,
+	"=le=":  FilterLte,
+	"<=":    FilterLte,
+	"=in=":  FilterIn,
+	"=out=": FilterNotIn,
+}
+
+// ParseRSQLFilter parses RSQL/FIQL expression and compiles it into a Selector with the fields of the schema:
+//
+//	status==active;(age=ge=18,role=in=(admin,owner))
+//
+// ";" is AND, "," is OR, AND takes precedence and parentheses group expressions.
+// Comparison operators are ==, !=, =gt= (>), =ge= (>=), =lt= (<), =le= (<=), =in=, =out=
+// and filter operators by name, e.g. =contains= or =null=. Values with reserved characters
+// or spaces are quoted with ' or ", backslash escapes the next character in quoted values.
+// Errors are *RSQLError with the position in the expression.
+func ParseRSQLFilter(input string, schema FilterSchema, opts RSQLOptions) (Selector, error) {
+	node, err := ParseRSQL(input, opts)
+	if err != nil {
+		return nil, err
+	}
+	return node.Selector(schema)
+}
+
+// ParseRSQLCondition is the Condition counterpart of ParseRSQLFilter.
+func ParseRSQLCondition(input string, schema FilterSchema, opts RSQLOptions) (Condition, error) {
+	node, err := ParseRSQL(input, opts)
+	if err != nil {
+		return nil, err
+	}
+	return node.Condition(schema)
+}
+
+// ParseRSQL parses RSQL/FIQL expression into AST, see ParseRSQLFilter.
+func ParseRSQL(input string, opts RSQLOptions) (RSQLNode, error) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultRSQLMaxDepth
+	}
+	if opts.MaxTerms <= 0 {
+		opts.MaxTerms = DefaultRSQLMaxTerms
+	}
+	if opts.MaxListItems <= 0 {
+		opts.MaxListItems = DefaultRSQLMaxListItems
+	}
+
+	p := &rsqlParser{lexer: rsqlLexer{input: input}, opts: opts}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != rsqlEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return node, nil
+}
+
+type rsqlTokenKind int
+
+const (
+	rsqlEOF rsqlTokenKind = iota
+	rsqlValue
+	rsqlOperator
+	rsqlAnd
+	rsqlOr
+	rsqlLParen
+	rsqlRParen
+)
+
+type rsqlToken struct {
+	kind  rsqlTokenKind
+	value string
+	pos   int
+}
+
+func (t rsqlToken) String() string {
+	switch t.kind {
+	case rsqlEOF:
+		return "end of expression"
+	case rsqlValue:
+		return fmt.Sprintf("value %q", t.value)
+	case rsqlOperator:
+		return fmt.Sprintf("operator %q", t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+type rsqlLexer struct {
+	input string
+	pos   int
+}
+
+// isRSQLReserved reports whether the character can't be a part of unquoted values.
+func isRSQLReserved(c byte) bool {
+	return strings.IndexByte("\"'();,=!<> \t\r\n", c) >= 0
+}
+
+func (l *rsqlLexer) next() (rsqlToken, error) {
+	for l.pos < len(l.input) && strings.IndexByte(" \t\r\n", l.input[l.pos]) >= 0 {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return rsqlToken{kind: rsqlEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.input[l.pos]
+	switch c {
+	case ';':
+		l.pos++
+		return rsqlToken{kind: rsqlAnd, value: ";", pos: start}, nil
+	case ',':
+		l.pos++
+		return rsqlToken{kind: rsqlOr, value: ",", pos: start}, nil
+	case '(':
+		l.pos++
+		return rsqlToken{kind: rsqlLParen, value: "(", pos: start}, nil
+	case ')':
+		l.pos++
+		return rsqlToken{kind: rsqlRParen, value: ")", pos: start}, nil
+	case '"', '\'':
+		return l.quoted(c)
+	case '=', '!', '<', '>':
+		return l.operator()
+	}
+
+	for l.pos < len(l.input) && !isRSQLReserved(l.input[l.pos]) {
+		l.pos++
+	}
+	return rsqlToken{kind: rsqlValue, value: l.input[start:l.pos], pos: start}, nil
+}
+
+func (l *rsqlLexer) quoted(quote byte) (rsqlToken, error) {
+	start := l.pos
+	l.pos++
+
+	var b strings.Builder
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		switch {
+		case c == '\\' && l.pos+1 < len(l.input):
+			b.WriteByte(l.input[l.pos+1])
+			l.pos += 2
+		case c == quote:
+			l.pos++
+			return rsqlToken{kind: rsqlValue, value: b.String(), pos: start}, nil
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return rsqlToken{}, &RSQLError{Pos: start, Msg: "unterminated quoted value"}
+}
+
+func (l *rsqlLexer) operator() (rsqlToken, error) {
+	start := l.pos
+	rest := l.input[l.pos:]
+
+	switch {
+	case strings.HasPrefix(rest, "=="), strings.HasPrefix(rest, "!="),
+		strings.HasPrefix(rest, "<="), strings.HasPrefix(rest, ">="):
+		l.pos += 2
+	case rest[0] == '<' || rest[0] == '>':
+		l.pos++
+	case rest[0] == '=':
+		// =name=
+		end := 1
+		for end < len(rest) && (rest[end] >= 'a' && rest[end] <= 'z' || rest[end] >= 'A' && rest[end] <= 'Z' || rest[end] == '_') {
+			end++
+		}
+		if end == 1 || end >= len(rest) || rest[end] != '=' {
+			return rsqlToken{}, &RSQLError{Pos: start, Msg: "invalid comparison operator"}
+		}
+		l.pos += end + 1
+	default:
+		return rsqlToken{}, &RSQLError{Pos: start, Msg: "invalid comparison operator"}
+	}
+	return rsqlToken{kind: rsqlOperator, value: l.input[start:l.pos], pos: start}, nil
+}
+
+type rsqlParser struct {
+	lexer rsqlLexer
+	tok   rsqlToken
+	opts  RSQLOptions
+	depth int
+	terms int
+}
+
+func (p *rsqlParser) next() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *rsqlParser) errorf(format string, args ...any) error {
+	return &RSQLError{Pos: p.tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseOr parses and { "," and }.
+func (p *rsqlParser) parseOr() (RSQLNode, error) {
+	return p.parseLogical(RSQLOr, rsqlOr, p.parseAnd)
+}
+
+// parseAnd parses constraint { ";" constraint }.
+func (p *rsqlParser) parseAnd() (RSQLNode, error) {
+	return p.parseLogical(RSQLAnd, rsqlAnd, p.parseConstraint)
+}
+
+func (p *rsqlParser) parseLogical(op RSQLLogicalOp, sep rsqlTokenKind, operand func() (RSQLNode, error)) (RSQLNode, error) {
+	pos := p.tok.pos
+	node, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != sep {
+		return node, nil
+	}
+
+	nodes := []RSQLNode{node}
+	for p.tok.kind == sep {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		node, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return &RSQLLogical{Op: op, Nodes: nodes, Pos: pos}, nil
+}
+
+// parseConstraint parses "(" or ")" or comparison.
+func (p *rsqlParser) parseConstraint() (RSQLNode, error) {
+	if p.tok.kind != rsqlLParen {
+		return p.parseComparison()
+	}
+
+	p.depth++
+	if p.depth > p.opts.MaxDepth {
+		return nil, p.errorf("nesting depth exceeds %d", p.opts.MaxDepth)
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != rsqlRParen {
+		return nil, p.errorf("expected \")\", got %s", p.tok)
+	}
+	p.depth--
+	return node, p.next()
+}
+
+// parseComparison parses field operator arguments.
+func (p *rsqlParser) parseComparison() (RSQLNode, error) {
+	if p.tok.kind != rsqlValue || p.tok.value == "" {
+		return nil, p.errorf("expected field, got %s", p.tok)
+	}
+	p.terms++
+	if p.terms > p.opts.MaxTerms {
+		return nil, p.errorf("number of comparisons exceeds %d", p.opts.MaxTerms)
+	}
+
+	node := &RSQLComparison{Field: p.tok.value, Pos: p.tok.pos}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != rsqlOperator {
+		return nil, p.errorf("expected comparison operator, got %s", p.tok)
+	}
+	op, ok := rsqlOperators[p.tok.value]
+	if !ok {
+		op = FilterOp(strings.Trim(p.tok.value, "="))
+	}
+	node.Op = op
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	args, err := p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+	node.Args = args
+	return node, nil
+}
+
+// parseArguments parses "(" value { "," value } ")" or value.
+func (p *rsqlParser) parseArguments() ([]string, error) {
+	if p.tok.kind == rsqlValue {
+		value := p.tok.value
+		return []string{value}, p.next()
+	}
+	if p.tok.kind != rsqlLParen {
+		return nil, p.errorf("expected value, got %s", p.tok)
+	}
+
+	var args []string
+	for {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != rsqlValue {
+			return nil, p.errorf("expected value, got %s", p.tok)
+		}
+		if len(args) == p.opts.MaxListItems {
+			return nil, p.errorf("number of arguments exceeds %d", p.opts.MaxListItems)
+		}
+		args = append(args, p.tok.value)
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		switch p.tok.kind {
+		case rsqlOr:
+			continue
+		case rsqlRParen:
+			return args, p.next()
+		default:
+			return nil, p.errorf("expected \",\" or \")\", got %s", p.tok)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package bunutils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseRSQLFilter(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "and",
+			input: "status==active;age=ge=18",
+			want:  `WHERE (("test_model"."status" = 'active') AND ("test_model"."age" >= 18))`,
+		},
+		{
+			name:  "precedence",
+			input: "status==active;age=gt=18,age=lt=10",
+			want:  `WHERE (((("test_model"."status" = 'active') AND ("test_model"."age" > 18))) OR (("test_model"."age" < 10)))`,
+		},
+		{
+			name:  "groups",
+			input: "status==active;(age=ge=18,status=in=(new,'on hold'))",
+			want:  `WHERE (("test_model"."status" = 'active') AND ((("test_model"."age" >= 18)) OR (("test_model"."status" IN ('new', 'on hold')))))`,
+		},
+		{
+			name:  "filter operators",
+			input: `name=contains="o'neil";created<2025-01-01T00:00:00Z`,
+			want:  `WHERE (("test_model"."full_name" ILIKE '%o''neil%' ESCAPE '\') AND ("test_model"."created_at" < '2025-01-01 00:00:00+00:00'))`,
+		},
+		{
+			name:  "single",
+			input: " active != false ",
+			want:  `WHERE ("test_model"."active" != FALSE)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := ParseRSQLFilter(tt.input, testFilterSchema, RSQLOptions{})
+			if err != nil {
+				t.Fatalf("ParseRSQLFilter() error = %v", err)
+			}
+
+			sql := selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRSQLCondition(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	cond, err := ParseRSQLCondition("status==archived,age=lt=18", testFilterSchema, RSQLOptions{})
+	if err != nil {
+		t.Fatalf("ParseRSQLCondition() error = %v", err)
+	}
+
+	sql := cond.Delete(db.NewDelete().Model((*testModel)(nil))).String()
+	want := `WHERE ((("test_model"."status" = 'archived')) OR (("test_model"."age" < 18)))`
+	if !strings.Contains(sql, want) {
+		t.Errorf("got %s, want it to contain %s", sql, want)
+	}
+}
+
+func TestParseRSQL(t *testing.T) {
+	node, err := ParseRSQL("status==active;(age=ge=18,role=in=(admin,owner))", RSQLOptions{})
+	if err != nil {
+		t.Fatalf("ParseRSQL() error = %v", err)
+	}
+
+	and, ok := node.(*RSQLLogical)
+	if !ok || and.Op != RSQLAnd || len(and.Nodes) != 2 {
+		t.Fatalf("ParseRSQL() = %#v, want AND of 2 nodes", node)
+	}
+	or, ok := and.Nodes[1].(*RSQLLogical)
+	if !ok || or.Op != RSQLOr || or.Pos != 16 {
+		t.Fatalf("ParseRSQL() second node = %#v, want OR at 16", and.Nodes[1])
+	}
+	in, ok := or.Nodes[1].(*RSQLComparison)
+	if !ok || in.Field != "role" || in.Op != FilterIn || strings.Join(in.Args, ",") != "admin,owner" || in.Pos != 26 {
+		t.Errorf("ParseRSQL() comparison = %#v", or.Nodes[1])
+	}
+}
+
+func TestParseRSQL_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  RSQLOptions
+		pos   int
+		msg   string
+	}{
+		{input: "", pos: 0, msg: "expected field, got end of expression"},
+		{input: "status", pos: 6, msg: "expected comparison operator"},
+		{input: "status=x", pos: 6, msg: "invalid comparison operator"},
+		{input: "status==", pos: 8, msg: "expected value"},
+		{input: "status=='active", pos: 8, msg: "unterminated quoted value"},
+		{input: "(status==a", pos: 10, msg: `expected ")"`},
+		{input: "status==a)", pos: 9, msg: `unexpected ")"`},
+		{input: "status=in=(a;b)", pos: 12, msg: `expected "," or ")"`},
+		{input: "((a==1))", opts: RSQLOptions{MaxDepth: 1}, pos: 1, msg: "nesting depth exceeds 1"},
+		{input: "a==1;a==2;a==3", opts: RSQLOptions{MaxTerms: 2}, pos: 10, msg: "number of comparisons exceeds 2"},
+		{input: "age=in=(1,2,3)", opts: RSQLOptions{MaxListItems: 2}, pos: 12, msg: "number of arguments exceeds 2"},
+		{input: "status==a;password==x", pos: 10, msg: `unknown field "password"`},
+		{input: "age=like=1", pos: 0, msg: `operator "like" is not allowed for field "age"`},
+		{input: "age>old", pos: 0, msg: `invalid int value "old"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseRSQLFilter(tt.input, testFilterSchema, tt.opts)
+
+			var rsqlErr *RSQLError
+			if !errors.As(err, &rsqlErr) {
+				t.Fatalf("ParseRSQLFilter() error = %v, want RSQLError", err)
+			}
+			if rsqlErr.Pos != tt.pos || !strings.Contains(rsqlErr.Msg, tt.msg) {
+				t.Errorf("ParseRSQLFilter() error = %v, want %q at offset %d", err, tt.msg, tt.pos)
+			}
+		})
+	}
+}
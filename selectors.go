@@ -1,11 +1,12 @@
 package bunutils
 
 import (
+	"bytes"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 )
 
 type Selector func(*bun.SelectQuery) *bun.SelectQuery
@@ -57,8 +58,39 @@ func Or(selectors ...Selector) Selector {
 	})...)
 }
 
+// Not adds a group to WHERE clause, prefixed by AND if there are other conditions before it,
+// in which the conditions of all Selectors are negated: NOT (...). It adds nothing if the Selectors
+// add no conditions, e.g. Not(WhereGt("id", 0)) with the skipped zero bound.
+func Not(selectors ...Selector) Selector {
+	apply := Apply(selectors...)
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if !addsWhere(q, apply) {
+			return q
+		}
+		// See CondNot for the empty condition before the group.
+		return q.Where("").WhereGroup("NOT ", apply)
+	}
+}
+
+// addsWhere reports whether apply adds conditions to q. bun doesn't expose the conditions of a query,
+// so a clone of q is rendered before and after apply. A clone which fails to render counts as changed.
+func addsWhere(q *bun.SelectQuery, apply Selector) bool {
+	if q.DB() == nil {
+		return true
+	}
+	probe := q.Clone()
+	fmter := q.DB().Formatter()
+
+	before, err := probe.AppendQuery(fmter, nil)
+	if err != nil {
+		return true
+	}
+	after, err := apply(probe).AppendQuery(fmter, nil)
+	return err != nil || !bytes.Equal(before, after)
+}
+
 // UseWhere allows to reuse the Where.Where() common logic as a Selector.
-func UseWhere(where Where) Selector {
+func UseWhere[ID comparable](where WhereOf[ID]) Selector {
 	return func(q *bun.SelectQuery) *bun.SelectQuery {
 		return where.Where(q)
 	}
@@ -66,130 +98,165 @@ func UseWhere(where Where) Selector {
 
 // WhereJsonbEqual compares a JSONB string field to a parameterized value safely using text extraction.
 func WhereJsonbEqual(col string, field string, value any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.?->>? = ?", bun.Ident(col), field, value)
-	}
+	return CondJsonbEqual(col, field, value).Select
 }
 
 // WhereJsonbPathEqual compares a JSONB string field located at the provided path to a value.
-// Path elements are applied in order using -> for intermediate levels and ->> for the final key.
+// The path is extracted as text with #>> operator, numeric path elements address array elements.
 func WhereJsonbPathEqual(col string, path []string, value any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where(jsonbPathExpression(path, true)+" = ?", bun.Ident(col), value)
-	}
+	return CondJsonbPathEqual(col, path, value).Select
 }
 
 // WhereJsonbObjectsArrayKeyValueEqual checks that a JSONB array of objects (at key) contains
 // an object where field == value. Built with JSONB functions to avoid string interpolation.
 func WhereJsonbObjectsArrayKeyValueEqual(col string, key, field string, value any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where(
-			`?TableAlias.? -> ? @> jsonb_build_array(jsonb_build_object(?::text, ?::text))`,
-			bun.Ident(col), key, field, value,
-		)
-	}
+	return CondJsonbObjectsArrayKeyValueEqual(col, key, field, value).Select
 }
 
 // WhereJsonbPathObjectsArrayKeyValueEqual checks that the JSONB array of objects located at the provided path
 // contains an object where field == value.
 func WhereJsonbPathObjectsArrayKeyValueEqual(col string, path []string, field string, value any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where(
-			jsonbPathExpression(path, false)+" @> jsonb_build_array(jsonb_build_object(?::text, ?::text))",
-			bun.Ident(col), field, value,
-		)
-	}
+	return CondJsonbPathObjectsArrayKeyValueEqual(col, path, field, value).Select
 }
 
-func jsonbPathExpression(path []string, text bool) string {
-	expr := "?TableAlias.?"
-	for idx, segment := range path {
-		if segment == "" {
-			continue
+// jsonbPathExpression returns the expression of the JSONB value located at the path in the column and its arguments.
+// The path is bound as text[] argument of #> operator, or #>> if text is true, so numeric segments address array elements.
+func jsonbPathExpression(q bun.QueryBuilder, col string, path []string, text bool) (string, []any) {
+	segments := make([]string, 0, len(path))
+	for _, segment := range path {
+		if segment != "" {
+			segments = append(segments, segment)
 		}
-		operator := "->"
-		if text && idx == len(path)-1 {
-			operator = "->>"
-		}
-		expr += fmt.Sprintf(" %s '%s'", operator, escapeJsonPathSegment(segment))
 	}
-	return expr
-}
+	if len(segments) == 0 {
+		return "?TableAlias.?", []any{column(q, col)}
+	}
 
-func escapeJsonPathSegment(segment string) string {
-	return strings.ReplaceAll(segment, "'", "''")
+	operator := "#>"
+	if text {
+		operator = "#>>"
+	}
+	return "?TableAlias.? " + operator + " ?", []any{column(q, col), pgdialect.Array(segments)}
 }
 
 func WhereEqual(col string, value any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? = ?", bun.Ident(col), value)
-	}
+	return CondEqual(col, value).Select
 }
 
 func WhereNull(col string) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? is null", bun.Ident(col))
-	}
+	return CondNull(col).Select
 }
 
 func WhereNotNull(col string) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? is not null", bun.Ident(col))
-	}
+	return CondNotNull(col).Select
 }
 
 func WhereDistinctOn(col string) Selector {
 	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		checkColumns(q.QueryBuilder(), col)
 		return q.DistinctOn(col).OrderExpr(fmt.Sprintf("%s, id", col))
 	}
 }
 
 func WhereNotEqual(col string, value any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? != ?", bun.Ident(col), value)
-	}
+	return CondNotEqual(col, value).Select
 }
 
 func WhereIn(col string, values any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? IN (?)", bun.Ident(col), bun.In(values))
-	}
+	return CondIn(col, values).Select
 }
 
 func WhereNotIn(col string, values any) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		q.Table()
-
-		return q.Where("?TableAlias.? NOT IN (?)", bun.Ident(col), bun.In(values))
-	}
+	return CondNotIn(col, values).Select
 }
 
+// WhereContains adds case-insensitive substring match, LIKE wildcards in substr are escaped.
+// It uses ILIKE on PostgreSQL and LOWER(col) LIKE LOWER(pattern) on other dialects.
 func WhereContains(col string, substr string) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? ILIKE ?", bun.Ident(col), "%"+substr+"%")
-	}
+	return CondContains(col, substr).Select
 }
 
+// WhereBegins adds case-insensitive prefix match, LIKE wildcards in substr are escaped.
+// It uses ILIKE on PostgreSQL and LOWER(col) LIKE LOWER(pattern) on other dialects.
 func WhereBegins(col string, substr string) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? ILIKE ?", bun.Ident(col), substr+"%")
-	}
+	return CondBegins(col, substr).Select
 }
 
+// WhereEnds adds case-insensitive suffix match, LIKE wildcards in substr are escaped.
+// It uses ILIKE on PostgreSQL and LOWER(col) LIKE LOWER(pattern) on other dialects.
 func WhereEnds(col string, substr string) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? ILIKE ?", bun.Ident(col), "%"+substr)
-	}
+	return CondEnds(col, substr).Select
+}
+
+// WhereContainsCaseSensitive adds case-sensitive substring match, LIKE wildcards in substr are escaped.
+func WhereContainsCaseSensitive(col string, substr string) Selector {
+	return CondContainsCaseSensitive(col, substr).Select
+}
+
+// WhereBeginsCaseSensitive adds case-sensitive prefix match, LIKE wildcards in substr are escaped.
+func WhereBeginsCaseSensitive(col string, substr string) Selector {
+	return CondBeginsCaseSensitive(col, substr).Select
+}
+
+// WhereEndsCaseSensitive adds case-sensitive suffix match, LIKE wildcards in substr are escaped.
+func WhereEndsCaseSensitive(col string, substr string) Selector {
+	return CondEndsCaseSensitive(col, substr).Select
+}
+
+// WhereLikeRaw adds case-insensitive LIKE match with the pattern passed as is, wildcards are not escaped.
+// Prefer WhereContains, WhereBegins and WhereEnds for user input.
+func WhereLikeRaw(col string, pattern string) Selector {
+	return CondLikeRaw(col, pattern).Select
 }
 
 func WhereBefore(col string, t time.Time) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? <= ?", bun.Ident(col), t)
-	}
+	return CondBefore(col, t).Select
 }
 
 func WhereAfter(col string, t time.Time) Selector {
-	return func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("?TableAlias.? >= ?", bun.Ident(col), t)
-	}
+	return CondAfter(col, t).Select
+}
+
+// WhereGt adds col > value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func WhereGt(col string, value any) Selector {
+	return CondGt(col, value).Selector()
+}
+
+// WhereGte adds col >= value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func WhereGte(col string, value any) Selector {
+	return CondGte(col, value).Selector()
+}
+
+// WhereLt adds col < value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func WhereLt(col string, value any) Selector {
+	return CondLt(col, value).Selector()
+}
+
+// WhereLte adds col <= value condition. Nil or zero value is skipped, pass a pointer to compare with a zero value.
+func WhereLte(col string, value any) Selector {
+	return CondLte(col, value).Selector()
+}
+
+// WhereBetween adds inclusive col BETWEEN from AND to condition.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func WhereBetween(col string, from, to any) Selector {
+	return CondBetween(col, from, to).Selector()
+}
+
+// WhereNotBetween adds inclusive col NOT BETWEEN from AND to condition.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func WhereNotBetween(col string, from, to any) Selector {
+	return CondNotBetween(col, from, to).Selector()
+}
+
+// WhereRange checks that col is within the range from..to, bounds define which ends are included.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func WhereRange(col string, from, to any, bounds Bounds) Selector {
+	return CondRange(col, from, to, bounds).Selector()
+}
+
+// WhereNotRange checks that col is outside the range from..to, bounds define which ends belong to the range.
+// Nil or zero bound is skipped, so the range becomes half-open.
+func WhereNotRange(col string, from, to any, bounds Bounds) Selector {
+	return CondNotRange(col, from, to, bounds).Selector()
 }
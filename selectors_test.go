@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
 )
 
 type testModel struct {
@@ -257,6 +258,115 @@ func TestWhereEnds(t *testing.T) {
 	}
 }
 
+func TestWhereCaseSensitive(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{name: "contains", selector: WhereContainsCaseSensitive("name", "test"), want: `"test_model"."name" LIKE '%test%' ESCAPE '\'`},
+		{name: "begins", selector: WhereBeginsCaseSensitive("name", "test"), want: `"test_model"."name" LIKE 'test%'`},
+		{name: "ends", selector: WhereEndsCaseSensitive("name", "test"), want: `"test_model"."name" LIKE '%test'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) || strings.Contains(sql, "ILIKE") {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+}
+
+func TestLikeExpression(t *testing.T) {
+	tests := []struct {
+		name          string
+		dialect       dialect.Name
+		caseSensitive bool
+		want          string
+	}{
+		{name: "pg", dialect: dialect.PG, want: "?TableAlias.? ILIKE ?"},
+		{name: "pg case-sensitive", dialect: dialect.PG, caseSensitive: true, want: "?TableAlias.? LIKE ?"},
+		{name: "mysql", dialect: dialect.MySQL, want: "LOWER(?TableAlias.?) LIKE LOWER(?)"},
+		{name: "mysql case-sensitive", dialect: dialect.MySQL, caseSensitive: true, want: "?TableAlias.? LIKE CAST(? AS BINARY)"},
+		{name: "sqlite", dialect: dialect.SQLite, want: "LOWER(?TableAlias.?) LIKE LOWER(?)"},
+		{name: "mssql", dialect: dialect.MSSQL, want: "LOWER(?TableAlias.?) LIKE LOWER(?)"},
+		{name: "mssql case-sensitive", dialect: dialect.MSSQL, caseSensitive: true, want: "?TableAlias.? COLLATE Latin1_General_CS_AS LIKE ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := likeExpression(tt.dialect, tt.caseSensitive)
+			if got != tt.want {
+				t.Errorf("likeExpression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereContainsEscaping(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	sql := WhereContains("name", `50%_\`)(query).String()
+
+	want := `"test_model"."name" ILIKE '%50\%\_\\%' ESCAPE '\'`
+	if !strings.Contains(sql, want) {
+		t.Errorf("WhereContains() should escape LIKE wildcards, got %s", sql)
+	}
+}
+
+func TestWhereLikeRaw(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	sql := WhereLikeRaw("name", "jo_n%")(query).String()
+
+	if !strings.Contains(sql, `"test_model"."name" ILIKE 'jo_n%'`) || strings.Contains(sql, "ESCAPE") {
+		t.Errorf("WhereLikeRaw() should pass the pattern as is, got %s", sql)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect dialect.Name
+		value   string
+		want    string
+	}{
+		{name: "no special chars", dialect: dialect.PG, value: "john", want: "john"},
+		{name: "percent", dialect: dialect.PG, value: "50%", want: `50\%`},
+		{name: "underscore", dialect: dialect.MySQL, value: "a_b", want: `a\_b`},
+		{name: "escape char", dialect: dialect.SQLite, value: `a\b`, want: `a\\b`},
+		{name: "bracket", dialect: dialect.PG, value: "[a]", want: "[a]"},
+		{name: "mssql bracket", dialect: dialect.MSSQL, value: "[a]", want: `\[a]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := escapeLike(tt.dialect, tt.value)
+			if got != tt.want {
+				t.Errorf("escapeLike() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeGlob(t *testing.T) {
+	got := escapeGlob("a*b?c[d]")
+	want := "a[*]b[?]c[[]d]"
+
+	if got != want {
+		t.Errorf("escapeGlob() = %v, want %v", got, want)
+	}
+}
+
 func TestWhereBefore(t *testing.T) {
 	db := newTestDB()
 	defer db.Close()
@@ -365,6 +475,9 @@ func TestWhereJsonbPathObjectsArrayKeyValueEqual(t *testing.T) {
 }
 
 func TestJsonbPathExpression(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
 	tests := []struct {
 		name string
 		path []string
@@ -375,86 +488,288 @@ func TestJsonbPathExpression(t *testing.T) {
 			name: "single path element with text",
 			path: []string{"user"},
 			text: true,
-			want: "?TableAlias.? ->> 'user'",
+			want: `"test_model"."metadata" #>> '{"user"}'`,
 		},
 		{
 			name: "multiple path elements with text",
 			path: []string{"user", "profile", "name"},
 			text: true,
-			want: "?TableAlias.? -> 'user' -> 'profile' ->> 'name'",
+			want: `"test_model"."metadata" #>> '{"user","profile","name"}'`,
 		},
 		{
 			name: "multiple path elements without text",
 			path: []string{"user", "profile"},
 			text: false,
-			want: "?TableAlias.? -> 'user' -> 'profile'",
+			want: `"test_model"."metadata" #> '{"user","profile"}'`,
 		},
 		{
 			name: "empty string in path",
 			path: []string{"user", "", "name"},
 			text: true,
-			want: "?TableAlias.? -> 'user' ->> 'name'",
+			want: `"test_model"."metadata" #>> '{"user","name"}'`,
+		},
+		{
+			name: "array index",
+			path: []string{"items", "0", "id"},
+			text: true,
+			want: `"test_model"."metadata" #>> '{"items","0","id"}'`,
+		},
+		{
+			name: "quotes are bound",
+			path: []string{"user's", "na?me"},
+			text: true,
+			want: `"test_model"."metadata" #>> '{"user''s","na?me"}'`,
+		},
+		{
+			name: "empty path",
+			path: nil,
+			text: true,
+			want: `"test_model"."metadata"`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := jsonbPathExpression(tt.path, tt.text)
-			if got != tt.want {
-				t.Errorf("jsonbPathExpression() = %v, want %v", got, tt.want)
+			q := db.NewSelect().Model((*testModel)(nil))
+			expr, args := jsonbPathExpression(q.QueryBuilder(), "metadata", tt.path, tt.text)
+			sql := q.Where(expr+" IS NOT NULL", args...).String()
+
+			if !strings.Contains(sql, "WHERE ("+tt.want+" IS NOT NULL)") {
+				t.Errorf("jsonbPathExpression() = %v, want it to contain %v", sql, tt.want)
 			}
 		})
 	}
 }
 
-func TestEscapeJsonPathSegment(t *testing.T) {
+func TestUseWhere(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	where := &Where{
+		IDs: []string{"1", "2", "3"},
+	}
+
+	query := db.NewSelect().Model((*testModel)(nil))
+	selector := UseWhere(*where)
+
+	result := selector(query)
+	sql := result.String()
+
+	if !strings.Contains(sql, "id") || !strings.Contains(sql, "IN") {
+		t.Error("UseWhere() should apply Where struct conditions")
+	}
+}
+
+func TestWhereComparisons(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
 	tests := []struct {
-		name    string
-		segment string
-		want    string
+		name     string
+		selector Selector
+		want     string
 	}{
+		{name: "gt", selector: WhereGt("age", 18), want: `"test_model"."age" > 18`},
+		{name: "gte", selector: WhereGte("age", 18), want: `"test_model"."age" >= 18`},
+		{name: "lt", selector: WhereLt("name", "m"), want: `"test_model"."name" < 'm'`},
+		{name: "lte", selector: WhereLte("age", 65.5), want: `"test_model"."age" <= 65.5`},
+		{name: "zero pointer", selector: WhereGte("age", ToPtr(0)), want: `"test_model"."age" >= 0`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("nil and zero values are skipped", func(t *testing.T) {
+		for _, selector := range []Selector{
+			WhereGt("age", nil),
+			WhereGte("age", 0),
+			WhereLt("created_at", time.Time{}),
+			WhereLte("age", (*int)(nil)),
+		} {
+			if selector != nil {
+				t.Error("zero bound should give a nil Selector")
+			}
+			sql := db.NewSelect().Model((*testModel)(nil)).Apply(selector).String()
+			if strings.Contains(sql, "WHERE") {
+				t.Errorf("zero bound should be skipped, got %s", sql)
+			}
+		}
+	})
+}
+
+func TestWhereBetween(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "between",
+			selector: WhereBetween("age", 18, 65),
+			want:     `"test_model"."age" BETWEEN 18 AND 65`,
+		},
+		{
+			name:     "between without lower bound",
+			selector: WhereBetween("age", nil, 65),
+			want:     `WHERE ("test_model"."age" <= 65)`,
+		},
+		{
+			name:     "between without upper bound",
+			selector: WhereBetween("age", 18, 0),
+			want:     `WHERE ("test_model"."age" >= 18)`,
+		},
+		{
+			name:     "not between",
+			selector: WhereNotBetween("age", 18, 65),
+			want:     `"test_model"."age" NOT BETWEEN 18 AND 65`,
+		},
+		{
+			name:     "not between without upper bound",
+			selector: WhereNotBetween("age", 18, nil),
+			want:     `WHERE ("test_model"."age" < 18)`,
+		},
+		{
+			name:     "exclusive range",
+			selector: WhereRange("age", 18, 65, BoundsExclusive),
+			want:     `"test_model"."age" > 18 AND "test_model"."age" < 65`,
+		},
 		{
-			name:    "no special chars",
-			segment: "user",
-			want:    "user",
+			name:     "lower inclusive range",
+			selector: WhereRange("age", 18, 65, BoundsLowerInclusive),
+			want:     `"test_model"."age" >= 18 AND "test_model"."age" < 65`,
 		},
 		{
-			name:    "single quote",
-			segment: "user's",
-			want:    "user''s",
+			name:     "upper inclusive range",
+			selector: WhereRange("age", 18, 65, BoundsUpperInclusive),
+			want:     `"test_model"."age" > 18 AND "test_model"."age" <= 65`,
 		},
 		{
-			name:    "multiple quotes",
-			segment: "it's user's",
-			want:    "it''s user''s",
+			name:     "lower inclusive range without lower bound",
+			selector: WhereRange("age", nil, 65, BoundsLowerInclusive),
+			want:     `WHERE ("test_model"."age" < 65)`,
+		},
+		{
+			name:     "exclusive not range",
+			selector: WhereNotRange("age", 18, 65, BoundsExclusive),
+			want:     `"test_model"."age" <= 18 OR "test_model"."age" >= 65`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := escapeJsonPathSegment(tt.segment)
-			if got != tt.want {
-				t.Errorf("escapeJsonPathSegment() = %v, want %v", got, tt.want)
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
 			}
 		})
 	}
+
+	t.Run("both bounds skipped", func(t *testing.T) {
+		selector := WhereBetween("age", nil, 0)
+		if selector != nil {
+			t.Error("WhereBetween() without bounds should give a nil Selector")
+		}
+		sql := db.NewSelect().Model((*testModel)(nil)).Apply(selector).String()
+		if strings.Contains(sql, "WHERE") {
+			t.Errorf("WhereBetween() without bounds should not add WHERE, got %s", sql)
+		}
+	})
 }
 
-func TestUseWhere(t *testing.T) {
+func TestNot(t *testing.T) {
 	db := newTestDB()
 	defer db.Close()
 
-	where := &Where{
-		IDs: []string{"1", "2", "3"},
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+	}{
+		{
+			name:     "single condition",
+			selector: Not(WhereEqual("name", "test")),
+			want:     `WHERE NOT (("test_model"."name" = 'test'))`,
+		},
+		{
+			name:     "multiple conditions",
+			selector: Not(WhereEqual("name", "test"), WhereNotNull("id")),
+			want:     `WHERE NOT (("test_model"."name" = 'test') AND ("test_model"."id" is not null))`,
+		},
+		{
+			name: "after other conditions",
+			selector: Apply(
+				WhereEqual("id", "1"),
+				Not(WhereEqual("name", "test")),
+			),
+			want: `WHERE ("test_model"."id" = '1') AND NOT (("test_model"."name" = 'test'))`,
+		},
+		{
+			name: "negated or",
+			selector: Not(Or(
+				WhereEqual("name", "a"),
+				WhereEqual("name", "b"),
+			)),
+			want: `WHERE NOT (((("test_model"."name" = 'a')) OR (("test_model"."name" = 'b'))))`,
+		},
+		{
+			name: "nested in or",
+			selector: Or(
+				WhereEqual("name", "a"),
+				Not(WhereEqual("id", "1")),
+			),
+			want: `WHERE ((("test_model"."name" = 'a')) OR (NOT (("test_model"."id" = '1'))))`,
+		},
+		{
+			name: "nested in and group",
+			selector: AndGroup(
+				WhereEqual("name", "a"),
+				Not(WhereEqual("id", "1")),
+			),
+			want: `WHERE (("test_model"."name" = 'a') AND NOT (("test_model"."id" = '1')))`,
+		},
 	}
 
-	query := db.NewSelect().Model((*testModel)(nil))
-	selector := UseWhere(*where)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("got %s, want it to contain %s", sql, tt.want)
+			}
+		})
+	}
 
-	result := selector(query)
-	sql := result.String()
+	empty := []struct {
+		name     string
+		selector Selector
+	}{
+		{name: "no selectors", selector: Not(nil)},
+		{name: "skipped selector", selector: Not(WhereGt("id", 0))},
+		{name: "skipped range", selector: Not(WhereBetween("id", 0, nil))},
+		{name: "skipped full text", selector: Not(WhereFullText(TsVector("name"), ""))},
+		{name: "empty group", selector: Not(AndGroup())},
+		{name: "empty expression", selector: ExprNot(ExprAnd()).Select},
+	}
+	for _, tt := range empty {
+		t.Run(tt.name, func(t *testing.T) {
+			want := db.NewSelect().Model((*testModel)(nil)).Apply(WhereEqual("name", "a")).String()
+			got := db.NewSelect().Model((*testModel)(nil)).Apply(WhereEqual("name", "a"), tt.selector).String()
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
 
-	if !strings.Contains(sql, "id") || !strings.Contains(sql, "IN") {
-		t.Error("UseWhere() should apply Where struct conditions")
+			alone := tt.selector(db.NewSelect().Model((*testModel)(nil))).String()
+			if strings.Contains(alone, "WHERE") {
+				t.Errorf("Not() should add nothing, got %s", alone)
+			}
+		})
 	}
 }
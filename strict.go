@@ -0,0 +1,133 @@
+package bunutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// StrictMode defines how columns which are not fields of the query model are reported, see WithStrictMode.
+type StrictMode int
+
+const (
+	// StrictOff doesn't check columns.
+	StrictOff StrictMode = iota
+	// StrictError sets *UnknownColumnError as the query error, it is returned when the query is executed.
+	StrictError
+	// StrictPanic panics with *UnknownColumnError when the query is built.
+	StrictPanic
+)
+
+// strictModeArg is the named argument of bun.DB which holds the strictModeValue. Named arguments are
+// the only settings which copies of bun.DB pass on, and bun reads them only when it formats a query.
+const strictModeArg = "bunutils_strict_mode"
+
+// strictModeValue is formatted as a marker which other values of the named argument don't produce:
+// strings are quoted and numbers have no prefix.
+type strictModeValue StrictMode
+
+func (v strictModeValue) String() string {
+	return fmt.Sprintf("bunutils:strict:%d", v)
+}
+
+func (v strictModeValue) AppendQuery(_ schema.Formatter, b []byte) ([]byte, error) {
+	return append(b, v.String()...), nil
+}
+
+// UnknownColumnError is reported in strict mode for columns which are not fields of the query model.
+type UnknownColumnError struct {
+	Table  string
+	Column string
+}
+
+func (e *UnknownColumnError) Error() string {
+	return fmt.Sprintf("bunutils: unknown column %q of table %q", e.Column, e.Table)
+}
+
+// WithStrictMode returns a copy of db which checks the columns used by selectors, conditions, Expr and Where
+// against the fields of the query model's schema.Table when queries are built:
+//
+//	db = bunutils.WithStrictMode(db, bunutils.StrictPanic)
+//	db.NewSelect().Model(&users).Apply(bunutils.WhereEqual("stauts", "active")) // panics
+//
+// Transactions, relation subqueries and further copies of the copy made with WithNamedArg are checked too.
+// Qualified names, expressions and queries without a table model are not checked.
+func WithStrictMode(db *bun.DB, mode StrictMode) *bun.DB {
+	return db.WithNamedArg(strictModeArg, strictModeValue(mode))
+}
+
+// CheckColumns applies each of the selectors to a SELECT query of the model in StrictError mode
+// and returns the errors of the queries. It is intended for tests:
+//
+//	if err := bunutils.CheckColumns(db, (*User)(nil), selectors...); err != nil {
+//	    t.Fatal(err)
+//	}
+func CheckColumns(db *bun.DB, model any, selectors ...Selector) error {
+	db = WithStrictMode(db, StrictError)
+
+	var errs []error
+	for i, selector := range selectors {
+		q := db.NewSelect().Model(model).Apply(selector)
+		if _, err := q.AppendQuery(db.Formatter(), nil); err != nil {
+			errs = append(errs, fmt.Errorf("selector %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// strictModeOf returns the StrictMode of db. Without the named argument, or with a value which isn't
+// a strictModeValue, it is StrictOff.
+func strictModeOf(db *bun.DB) StrictMode {
+	arg := db.Formatter().FormatQuery("?" + strictModeArg)
+	for _, mode := range []StrictMode{StrictError, StrictPanic} {
+		if arg == strictModeValue(mode).String() {
+			return mode
+		}
+	}
+	return StrictOff
+}
+
+// column returns the identifier of the column of the query model, checking it in strict mode.
+func column(q bun.QueryBuilder, col string) bun.Ident {
+	checkColumns(q, col)
+	return bun.Ident(col)
+}
+
+// checkColumns checks the columns of the query model in strict mode.
+func checkColumns(q bun.QueryBuilder, cols ...string) {
+	query, ok := q.Unwrap().(interface {
+		DB() *bun.DB
+		GetModel() bun.Model
+	})
+	if !ok || query.DB() == nil {
+		return
+	}
+
+	mode := strictModeOf(query.DB())
+	if mode == StrictOff {
+		return
+	}
+	model, ok := query.GetModel().(bun.TableModel)
+	if !ok {
+		return
+	}
+
+	table := model.Table()
+	for _, col := range cols {
+		if strings.ContainsAny(col, `.(*" `) {
+			continue
+		}
+		if _, ok := table.FieldMap[col]; ok {
+			continue
+		}
+
+		err := &UnknownColumnError{Table: table.Name, Column: col}
+		if mode == StrictPanic {
+			panic(err)
+		}
+		setQueryErr(q, err)
+	}
+}
@@ -0,0 +1,127 @@
+package bunutils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+func TestWithStrictMode(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+	db.RegisterModel((*relUserGroup)(nil))
+
+	t.Run("error", func(t *testing.T) {
+		strict := WithStrictMode(db, StrictError)
+		q := strict.NewSelect().Model((*testModel)(nil)).Apply(WhereEqual("name", "a"), WhereEqual("stauts", "active"))
+
+		_, err := q.AppendQuery(strict.Formatter(), nil)
+		var colErr *UnknownColumnError
+		if !errors.As(err, &colErr) || colErr.Column != "stauts" || colErr.Table != "test" {
+			t.Fatalf("AppendQuery() error = %v, want UnknownColumnError", err)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		strict := WithStrictMode(db, StrictPanic)
+		defer func() {
+			err, _ := recover().(error)
+			if err == nil || !strings.Contains(err.Error(), `unknown column "stauts" of table "test"`) {
+				t.Errorf("recover() = %v, want UnknownColumnError", err)
+			}
+		}()
+		strict.NewSelect().Model((*testModel)(nil)).Apply(CondEqual("stauts", "active").Select)
+		t.Error("strict mode should panic")
+	})
+
+	t.Run("off", func(t *testing.T) {
+		q := db.NewSelect().Model((*testModel)(nil)).Apply(WhereEqual("stauts", "active"))
+		if _, err := q.AppendQuery(db.Formatter(), nil); err != nil {
+			t.Errorf("AppendQuery() error = %v, columns should not be checked", err)
+		}
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		for name, db := range map[string]*bun.DB{
+			"original":     db,
+			"int value":    db.WithNamedArg("bunutils_strict_mode", int(StrictPanic)),
+			"string value": db.WithNamedArg("bunutils_strict_mode", "bunutils:strict:2"),
+			"turned off":   WithStrictMode(WithStrictMode(db, StrictPanic), StrictOff),
+		} {
+			if mode := strictModeOf(db); mode != StrictOff {
+				t.Errorf("strictModeOf(%s) = %v, want StrictOff", name, mode)
+			}
+		}
+	})
+
+	t.Run("copies", func(t *testing.T) {
+		strict := WithStrictMode(db, StrictPanic)
+		for name, db := range map[string]*bun.DB{
+			"strict":    strict,
+			"named arg": strict.WithNamedArg("tenant", "a"),
+		} {
+			if mode := strictModeOf(db); mode != StrictPanic {
+				t.Errorf("strictModeOf(%s) = %v, want StrictPanic", name, mode)
+			}
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		strict := WithStrictMode(db, StrictError)
+		tx, err := strict.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+
+		q := tx.NewSelect().Model((*testModel)(nil)).Apply(WhereIn("stauts", []string{"a"}))
+		if _, err := q.AppendQuery(strict.Formatter(), nil); err == nil {
+			t.Error("AppendQuery() should return an error in transaction")
+		}
+	})
+
+	t.Run("unchecked", func(t *testing.T) {
+		strict := WithStrictMode(db, StrictPanic)
+		strict.NewSelect().Model((*testModel)(nil)).Apply(WhereEqual("id", "1"), WhereDistinctOn("u.name"))
+		strict.NewSelect().Table("test").Apply(WhereEqual("anything", 1))
+	})
+}
+
+func TestCheckColumns(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+	db.RegisterModel((*relUserGroup)(nil))
+
+	err := CheckColumns(db, (*relUser)(nil),
+		WhereEqual("id", 1),
+		WhereHas("Orders", WhereEqual("status", "paid"), WhereGt("total", 10)),
+		UseWhere(Where{IDCol: "uuid", ID: "x"}),
+		(&Where{SelectColumns: []string{"id", "email"}}).Select,
+		ExprNot(ExprContains("name", "a")).Select,
+	)
+
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	}
+	for _, want := range []string{
+		`selector 1: bunutils: unknown column "total" of table "orders"`,
+		`selector 2: bunutils: unknown column "uuid" of table "users"`,
+		`selector 3: bunutils: unknown column "email" of table "users"`,
+		`selector 4: bunutils: unknown column "name" of table "users"`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("CheckColumns() error = %s, want it to contain %s", msg, want)
+		}
+	}
+	if strings.Contains(msg, "selector 0") {
+		t.Errorf("CheckColumns() error = %s, selector 0 is valid", msg)
+	}
+
+	if err := CheckColumns(db, (*testModel)(nil), WhereEqual("name", "a"), OrderBySimilarity("name", "a")); err != nil {
+		t.Errorf("CheckColumns() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,69 @@
+package bunutils
+
+import (
+	"github.com/uptrace/bun"
+)
+
+// DefaultSimilarityThreshold is the trigram similarity threshold used when threshold is not positive,
+// the same as the pg_trgm.similarity_threshold default.
+const DefaultSimilarityThreshold = 0.3
+
+// CondSimilar is the Condition counterpart of WhereSimilar.
+func CondSimilar(col string, text string, threshold float64) Condition {
+	if threshold <= 0 {
+		threshold = DefaultSimilarityThreshold
+	}
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("similarity(?TableAlias.?, ?) >= ?", column(q, col), text, threshold)
+	}
+}
+
+// WhereSimilar checks that trigram similarity of the column and the text is at least threshold (0..1].
+// The threshold is passed as a query argument instead of relying on the % operator,
+// which depends on pg_trgm.similarity_threshold session setting.
+// DefaultSimilarityThreshold is used if threshold is not positive.
+//
+// The similarity() function can't use trigram GIN or GiST indexes, so the whole table is scanned.
+// Use WhereTrigramMatch on large tables.
+//
+// Note: PostgreSQL only, requires pg_trgm extension.
+func WhereSimilar(col string, text string, threshold float64) Selector {
+	return CondSimilar(col, text, threshold).Select
+}
+
+// CondTrigramMatch is the Condition counterpart of WhereTrigramMatch.
+func CondTrigramMatch(col string, text string) Condition {
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		return q.Where("?TableAlias.? % ?", column(q, col), text)
+	}
+}
+
+// WhereTrigramMatch checks that trigram similarity of the column and the text is at least
+// pg_trgm.similarity_threshold with the % operator, which can use trigram GIN and GiST indexes.
+// The threshold is set per session or transaction, e.g. SET LOCAL pg_trgm.similarity_threshold = 0.4.
+//
+// Note: PostgreSQL only, requires pg_trgm extension.
+func WhereTrigramMatch(col string, text string) Selector {
+	return CondTrigramMatch(col, text).Select
+}
+
+// OrderBySimilarity orders rows by trigram distance (<->) between the column and the text, most similar first.
+// With a GiST trigram index and Limit it is executed as an index nearest-neighbor search.
+//
+// Note: PostgreSQL only, requires pg_trgm extension.
+func OrderBySimilarity(col string, text string) Selector {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.OrderExpr("?TableAlias.? <-> ?", column(q.QueryBuilder(), col), text)
+	}
+}
+
+// SelectSimilarity adds trigram similarity score of the column and the text as a column with the provided alias.
+// The selected columns are replaced the same way as with bun.SelectQuery.ColumnExpr,
+// add Column or ColumnExpr("?TableColumns") to keep the model columns.
+//
+// Note: PostgreSQL only, requires pg_trgm extension.
+func SelectSimilarity(col string, text string, alias string) Selector {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.ColumnExpr("similarity(?TableAlias.?, ?) AS ?", column(q.QueryBuilder(), col), text, bun.Ident(alias))
+	}
+}
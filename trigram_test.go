@@ -0,0 +1,65 @@
+package bunutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWhereSimilar(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	t.Run("with threshold", func(t *testing.T) {
+		sql := WhereSimilar("name", "jon", 0.5)(db.NewSelect().Model((*testModel)(nil))).String()
+		want := `similarity("test_model"."name", 'jon') >= 0.5`
+
+		if !strings.Contains(sql, want) {
+			t.Errorf("WhereSimilar() = %s, want it to contain %s", sql, want)
+		}
+	})
+
+	t.Run("default threshold", func(t *testing.T) {
+		sql := WhereSimilar("name", "jon", 0)(db.NewSelect().Model((*testModel)(nil))).String()
+		want := `similarity("test_model"."name", 'jon') >= 0.3`
+
+		if !strings.Contains(sql, want) {
+			t.Errorf("WhereSimilar() = %s, want it to contain %s", sql, want)
+		}
+	})
+}
+
+func TestWhereTrigramMatch(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	sql := WhereTrigramMatch("name", "jon")(db.NewSelect().Model((*testModel)(nil))).String()
+	want := `"test_model"."name" % 'jon'`
+
+	if !strings.Contains(sql, want) {
+		t.Errorf("WhereTrigramMatch() = %s, want it to contain %s", sql, want)
+	}
+}
+
+func TestOrderBySimilarity(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	sql := OrderBySimilarity("name", "jon")(db.NewSelect().Model((*testModel)(nil))).String()
+	want := `ORDER BY "test_model"."name" <-> 'jon'`
+
+	if !strings.Contains(sql, want) {
+		t.Errorf("OrderBySimilarity() = %s, want it to contain %s", sql, want)
+	}
+}
+
+func TestSelectSimilarity(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	sql := SelectSimilarity("name", "jon", "score")(db.NewSelect().Model((*testModel)(nil))).String()
+	want := `SELECT similarity("test_model"."name", 'jon') AS "score"`
+
+	if !strings.Contains(sql, want) {
+		t.Errorf("SelectSimilarity() = %s, want it to contain %s", sql, want)
+	}
+}
@@ -1,10 +1,16 @@
 package bunutils
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
 )
 
 const (
@@ -14,10 +20,15 @@ const (
 	DefaultUpdatedAtCol = "updated_at"
 )
 
-type Where struct {
-	ID       string   `json:"id,omitempty" form:"id"`
-	IDs      []string `json:"ids,omitempty" form:"ids"`
-	NotInIDs []string `json:"not_in_ids,omitempty" form:"not_in_ids"`
+// Where is WhereOf with string IDs.
+type Where = WhereOf[string]
+
+// WhereOf is a set of common filters, pagination and sorting of list queries,
+// with IDs of the model primary key type, e.g. WhereOf[int64] or WhereOf[uuid.UUID].
+type WhereOf[ID comparable] struct {
+	ID       ID   `json:"id,omitempty" form:"id"`
+	IDs      []ID `json:"ids,omitempty" form:"ids"`
+	NotInIDs []ID `json:"not_in_ids,omitempty" form:"not_in_ids"`
 
 	HasFlags    []int `json:"has_flags,omitempty" form:"has_flags"`
 	HasNotFlags []int `json:"has_not_flags,omitempty" form:"has_not_flags"`
@@ -28,6 +39,11 @@ type Where struct {
 	Limit  *int `json:"limit,omitempty" form:"limit"`
 	Offset *int `json:"offset,omitempty" form:"offset"`
 
+	Cursor string `json:"cursor,omitempty" form:"cursor"`
+
+	// IDCol is the column of ID, IDs and NotInIDs. If not set, it is taken from the registered defaults
+	// of the query model, then from its single primary key, then DefaultIDCol.
+	IDCol        string `json:"id_col,omitempty" form:"id_col"`
 	FlagsCol     string `json:"flags_col,omitempty" form:"flags_col"`
 	CreatedAtCol string `json:"created_at_col,omitempty" form:"created_at_col"`
 	UpdatedAtCol string `json:"updated_at_col,omitempty" form:"updated_at_col"`
@@ -41,72 +57,157 @@ type Where struct {
 	SelectColumns  []string `json:"select_columns,omitempty" form:"select_columns"`
 	ExcludeColumns []string `json:"exclude_columns,omitempty" form:"exclude_columns"`
 
+	// SortBy is a key of Order. The zero SortBy selects the key 0, so Order keys start at 1
+	// when the zero SortBy means no sort.
 	SortBy   int  `json:"sort_by,omitempty" form:"sort_by"`
 	SortDesc bool `json:"sort_desc,omitempty" form:"sort_desc"`
 
+	// Sorts are sort keys of Order, they take precedence over SortBy and SortDesc.
+	Sorts []Sort `json:"sorts,omitempty" form:"sorts"`
+	// DefaultSorts are used if neither Sorts nor SortBy are set. They can't be used with the key 0 of Order,
+	// which is always selected by the zero SortBy, ValidateSorts reports it.
+	DefaultSorts []Sort `json:"-"`
+
 	Order Order `json:"-"`
 }
 
-func (w *Where) Where(q *bun.SelectQuery) *bun.SelectQuery {
+func (w *WhereOf[ID]) Where(q *bun.SelectQuery) *bun.SelectQuery {
+	return w.Condition().Select(q)
+}
+
+// Condition returns the filters of the Where as a Condition,
+// so they can also be applied to UPDATE and DELETE queries.
+// Column names which are not set are taken from the model defaults, see RegisterWhereDefaults.
+func (w *WhereOf[ID]) Condition() Condition {
 	if w == nil {
-		return q
+		return nil
 	}
 
-	if w.FlagsCol == "" {
-		w.FlagsCol = DefaultFlagsCol
-	}
-	if w.CreatedAtCol == "" {
-		w.CreatedAtCol = DefaultCreatedAtCol
-	}
-	if w.UpdatedAtCol == "" {
-		w.UpdatedAtCol = DefaultUpdatedAtCol
-	}
+	return func(q bun.QueryBuilder) bun.QueryBuilder {
+		w := w.withDefaults(q.Unwrap())
 
-	if w.ID != "" {
-		q = q.Where("?TableAlias.? = ?", bun.Ident(DefaultIDCol), w.ID)
-	}
-	if len(w.IDs) > 0 {
-		q = q.Where("?TableAlias.? IN (?)", bun.Ident(DefaultIDCol), bun.In(w.IDs))
-	}
-	if len(w.NotInIDs) > 0 {
-		q = q.Where("?TableAlias.? NOT IN (?)", bun.Ident(DefaultIDCol), bun.In(w.NotInIDs))
-	}
+		var zero ID
+		if w.ID != zero {
+			q = q.Where("?TableAlias.? = ?", column(q, w.IDCol), w.ID)
+		}
+		if len(w.IDs) > 0 {
+			q = q.Where("?TableAlias.? IN (?)", column(q, w.IDCol), bun.In(w.IDs))
+		}
+		if len(w.NotInIDs) > 0 {
+			q = q.Where("?TableAlias.? NOT IN (?)", column(q, w.IDCol), bun.In(w.NotInIDs))
+		}
 
-	for _, flag := range w.HasFlags {
-		q = q.Where("?TableAlias.? & ? = ?", bun.Ident(DefaultFlagsCol), flag, flag)
-	}
-	for _, flag := range w.HasNotFlags {
-		q = q.Where("?TableAlias.? & ? = 0", bun.Ident(DefaultFlagsCol), flag)
-	}
+		for _, flag := range w.HasFlags {
+			q = q.Where("?TableAlias.? & ? = ?", column(q, w.FlagsCol), flag, flag)
+		}
+		for _, flag := range w.HasNotFlags {
+			q = q.Where("?TableAlias.? & ? = 0", column(q, w.FlagsCol), flag)
+		}
 
-	if w.OnlyDeleted {
-		q.WhereDeleted()
-	} else if w.WithDeleted {
-		q.WhereAllWithDeleted()
-	}
+		if w.OnlyDeleted {
+			q = q.WhereDeleted()
+		} else if w.WithDeleted {
+			q = q.WhereAllWithDeleted()
+		}
 
-	if w.CreatedAfter != nil {
-		q.Where("?TableAlias.? >= ?", bun.Ident(DefaultCreatedAtCol), time.UnixMilli(*w.CreatedAfter))
+		if w.CreatedAfter != nil {
+			q = q.Where("?TableAlias.? >= ?", column(q, w.CreatedAtCol), time.UnixMilli(*w.CreatedAfter))
+		}
+		if w.CreatedBefore != nil {
+			q = q.Where("?TableAlias.? <= ?", column(q, w.CreatedAtCol), time.UnixMilli(*w.CreatedBefore))
+		}
+
+		if w.UpdatedAfter != nil {
+			q = q.Where("?TableAlias.? >= ?", column(q, w.UpdatedAtCol), time.UnixMilli(*w.UpdatedAfter))
+		}
+		if w.UpdatedBefore != nil {
+			q = q.Where("?TableAlias.? <= ?", column(q, w.UpdatedAtCol), time.UnixMilli(*w.UpdatedBefore))
+		}
+
+		return q
 	}
-	if w.CreatedBefore != nil {
-		q.Where("?TableAlias.? <= ?", bun.Ident(DefaultCreatedAtCol), time.UnixMilli(*w.CreatedBefore))
+}
+
+var whereDefaults = struct {
+	sync.RWMutex
+	models map[reflect.Type]Where
+}{models: make(map[reflect.Type]Where)}
+
+// RegisterWhereDefaults registers the default Where configuration of the model: IDCol, FlagsCol,
+// CreatedAtCol, UpdatedAtCol, Order and DefaultSorts. They are used by Where of queries with the model
+// if not set in the Where itself. Other fields of the defaults are ignored.
+//
+//	bunutils.RegisterWhereDefaults((*User)(nil), bunutils.Where{
+//	    IDCol:        "uuid",
+//	    CreatedAtCol: "created",
+//	    Order:        bunutils.Order{1: "name", 2: "created"},
+//	})
+func RegisterWhereDefaults(model any, defaults Where) {
+	typ := modelType(model)
+
+	whereDefaults.Lock()
+	defer whereDefaults.Unlock()
+	whereDefaults.models[typ] = defaults
+}
+
+func modelType(model any) reflect.Type {
+	typ := reflect.TypeOf(model)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) {
+		typ = typ.Elem()
 	}
+	return typ
+}
+
+// withDefaults returns a copy of the Where with column names, Order and DefaultSorts which are not set
+// taken from the registered defaults of the query model. IDCol falls back to the single primary key
+// of the model, e.g. "uuid", so models without an "id" column don't need IDCol, and all the columns
+// to the Default*Col constants.
+func (w *WhereOf[ID]) withDefaults(query any) WhereOf[ID] {
+	r := *w
 
-	if w.UpdatedAfter != nil {
-		q.Where("?TableAlias.? >= ?", bun.Ident(DefaultUpdatedAtCol), time.UnixMilli(*w.UpdatedAfter))
+	var table *schema.Table
+	if q, ok := query.(interface{ GetModel() bun.Model }); ok {
+		if model, ok := q.GetModel().(bun.TableModel); ok {
+			table = model.Table()
+		}
 	}
-	if w.UpdatedBefore != nil {
-		q.Where("?TableAlias.? <= ?", bun.Ident(DefaultUpdatedAtCol), time.UnixMilli(*w.UpdatedBefore))
+
+	if table != nil {
+		whereDefaults.RLock()
+		defaults, ok := whereDefaults.models[table.Type]
+		whereDefaults.RUnlock()
+
+		if ok {
+			r.IDCol = cmp.Or(r.IDCol, defaults.IDCol)
+			r.FlagsCol = cmp.Or(r.FlagsCol, defaults.FlagsCol)
+			r.CreatedAtCol = cmp.Or(r.CreatedAtCol, defaults.CreatedAtCol)
+			r.UpdatedAtCol = cmp.Or(r.UpdatedAtCol, defaults.UpdatedAtCol)
+			if r.Order == nil {
+				r.Order = defaults.Order
+			}
+			if r.DefaultSorts == nil {
+				r.DefaultSorts = defaults.DefaultSorts
+			}
+		}
+		if r.IDCol == "" && len(table.PKs) == 1 {
+			r.IDCol = table.PKs[0].Name
+		}
 	}
 
-	return q
+	r.IDCol = cmp.Or(r.IDCol, DefaultIDCol)
+	r.FlagsCol = cmp.Or(r.FlagsCol, DefaultFlagsCol)
+	r.CreatedAtCol = cmp.Or(r.CreatedAtCol, DefaultCreatedAtCol)
+	r.UpdatedAtCol = cmp.Or(r.UpdatedAtCol, DefaultUpdatedAtCol)
+	return r
 }
 
-func (w *Where) Select(q *bun.SelectQuery) *bun.SelectQuery {
+func (w *WhereOf[ID]) Select(q *bun.SelectQuery) *bun.SelectQuery {
 	if w == nil {
 		return q
 	}
 
+	checkColumns(q.QueryBuilder(), w.SelectColumns...)
+	checkColumns(q.QueryBuilder(), w.ExcludeColumns...)
 	if len(w.SelectColumns) > 0 {
 		q = q.Column(w.SelectColumns...)
 	}
@@ -121,19 +222,149 @@ func (w *Where) Select(q *bun.SelectQuery) *bun.SelectQuery {
 		q = q.Offset(*w.Offset)
 	}
 
-	if col, ok := w.Order[w.SortBy]; ok {
-		if w.SortDesc {
-			q = q.Order(OrderDesc(col))
-		} else {
-			q = q.Order(OrderAsc(col))
-		}
+	sorted := w.withDefaults(q)
+	if err := sorted.ValidateSorts(); err != nil {
+		return q.Err(err)
+	}
+	for _, sort := range sorted.sorts() {
+		q = orderBySort(q, sorted.Order[sort.By], sort)
 	}
 
 	return q
 }
 
+// ValidateSorts checks that Sorts and DefaultSorts refer to the Order columns without duplicates,
+// and that DefaultSorts aren't combined with the key 0 of Order. Select fails the query with the same error.
+func (w *WhereOf[ID]) ValidateSorts() error {
+	if w == nil {
+		return nil
+	}
+	if _, ok := w.Order[0]; ok && len(w.DefaultSorts) > 0 {
+		return fmt.Errorf("%w: default sorts are never used with the key 0 of Order, start the keys at 1", ErrInvalidSort)
+	}
+
+	for _, sorts := range [][]Sort{w.Sorts, w.DefaultSorts} {
+		seen := make(map[int]bool, len(sorts))
+		for i, sort := range sorts {
+			if _, ok := w.Order[sort.By]; !ok {
+				return fmt.Errorf("%w: sort %d: unknown key %d", ErrInvalidSort, i, sort.By)
+			}
+			if seen[sort.By] {
+				return fmt.Errorf("%w: sort %d: duplicate key %d", ErrInvalidSort, i, sort.By)
+			}
+			if sort.Nulls < NullsDefault || sort.Nulls > NullsLast {
+				return fmt.Errorf("%w: sort %d: unknown nulls order %d", ErrInvalidSort, i, sort.Nulls)
+			}
+			seen[sort.By] = true
+		}
+	}
+	return nil
+}
+
+// sorts returns Sorts, SortBy with SortDesc if SortBy is in the Order, or DefaultSorts.
+func (w *WhereOf[ID]) sorts() []Sort {
+	if len(w.Sorts) > 0 {
+		return w.Sorts
+	}
+	if _, ok := w.Order[w.SortBy]; ok {
+		return []Sort{{By: w.SortBy, Desc: w.SortDesc}}
+	}
+	return w.DefaultSorts
+}
+
+func (w *WhereOf[ID]) pagination() (limit, offset *int) {
+	if w == nil {
+		return nil, nil
+	}
+	return w.Limit, w.Offset
+}
+
+// Keyset returns keyset pagination ordered by the sort columns of Order and limited by Limit,
+// starting from Cursor. IDCol, Order and DefaultSorts which are not set are taken from the defaults
+// of the query model, like in Where and Select. Apply it instead of Select ordering, limit and offset:
+//
+//	q := db.NewSelect().Model(&users)
+//	ks := where.Keyset(q, secret)
+//	err := q.Apply(where.Where, ks.Select).Scan(ctx)
+//	users, next, prev, err := bunutils.KeysetCursors(db, ks, users)
+func (w *WhereOf[ID]) Keyset(q *bun.SelectQuery, secret []byte) *Keyset {
+	if w == nil {
+		return &Keyset{Secret: secret}
+	}
+
+	var query any
+	if q != nil {
+		query = q
+	}
+	d := w.withDefaults(query)
+
+	k := &Keyset{IDCol: d.IDCol, Cursor: d.Cursor, Secret: secret}
+	for _, sort := range d.sorts() {
+		if col, ok := d.Order[sort.By]; ok {
+			k.Columns = append(k.Columns, SortColumn{Col: col, Desc: sort.Desc})
+		}
+	}
+	if w.Limit != nil {
+		k.Limit = *w.Limit
+	}
+	return k
+}
+
 type Order map[int]string
 
+// ErrInvalidSort is returned for Where sorts which are not in the Order.
+var ErrInvalidSort = errors.New("bunutils: invalid sort")
+
+// NullsOrder defines where NULL values are placed in the order.
+type NullsOrder int
+
+const (
+	// NullsDefault keeps the database default, NULLs are last in ascending order on PostgreSQL.
+	NullsDefault NullsOrder = iota
+	// NullsFirst places NULLs before other values.
+	NullsFirst
+	// NullsLast places NULLs after other values.
+	NullsLast
+)
+
+// Sort is a sort key of Where, By is a key of the Order.
+type Sort struct {
+	By    int        `json:"by" form:"by"`
+	Desc  bool       `json:"desc,omitempty" form:"desc"`
+	Nulls NullsOrder `json:"nulls,omitempty" form:"nulls"`
+}
+
+func orderBySort(q *bun.SelectQuery, col string, sort Sort) *bun.SelectQuery {
+	checkColumns(q.QueryBuilder(), col)
+	order := OrderAsc(col)
+	if sort.Desc {
+		order = OrderDesc(col)
+	}
+	if sort.Nulls == NullsDefault {
+		return q.Order(order)
+	}
+
+	switch q.Dialect().Name() {
+	case dialect.MySQL, dialect.MSSQL:
+		// NULLS FIRST and NULLS LAST are not supported, NULLs are ordered by a preceding expression.
+		if sort.Nulls == NullsFirst {
+			q = q.OrderExpr("CASE WHEN ? IS NULL THEN 0 ELSE 1 END", bun.Ident(col))
+		} else {
+			q = q.OrderExpr("CASE WHEN ? IS NULL THEN 1 ELSE 0 END", bun.Ident(col))
+		}
+		return q.Order(order)
+	}
+
+	nulls := " NULLS LAST"
+	if sort.Nulls == NullsFirst {
+		nulls = " NULLS FIRST"
+	}
+	if sort.Desc {
+		return q.OrderExpr("? desc"+nulls, bun.Ident(col))
+	}
+	return q.OrderExpr("? asc"+nulls, bun.Ident(col))
+}
+
 func OrderAsc(col string) string {
 	return fmt.Sprintf("%s asc", col)
 }
@@ -1,6 +1,8 @@
 package bunutils
 
 import (
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -160,8 +162,78 @@ func TestWhere_Where(t *testing.T) {
 		result := where.Where(query)
 
 		sql := result.String()
-		if !strings.Contains(sql, "flags") {
-			t.Error("Where() should use custom column names")
+		if !strings.Contains(sql, `"test_model"."custom_flags" & 1 = 1`) {
+			t.Errorf("Where() should use custom column names, got %s", sql)
+		}
+	})
+
+	t.Run("with custom ID column", func(t *testing.T) {
+		now := time.Now().UnixMilli()
+		where := &Where{
+			IDCol:        "uuid",
+			CreatedAtCol: "created",
+			UpdatedAtCol: "modified",
+			IDs:          []string{"a"},
+			CreatedAfter: &now,
+			UpdatedAfter: &now,
+		}
+		query := db.NewSelect().Model((*testModel)(nil))
+		sql := where.Where(query).String()
+
+		for _, want := range []string{`"test_model"."uuid" IN ('a')`, `"test_model"."created" >= `, `"test_model"."modified" >= `} {
+			if !strings.Contains(sql, want) {
+				t.Errorf("Where() = %s, want it to contain %s", sql, want)
+			}
+		}
+	})
+}
+
+func TestRegisterWhereDefaults(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	type uuidModel struct {
+		bun.BaseModel `bun:"table:uuids"`
+		UUID          string `bun:"uuid,pk"`
+	}
+
+	type registeredModel struct {
+		bun.BaseModel `bun:"table:registered"`
+		Key           string `bun:"key,pk"`
+	}
+
+	RegisterWhereDefaults((*registeredModel)(nil), Where{
+		IDCol:        "external_id",
+		FlagsCol:     "flags_mask",
+		Order:        Order{1: "created"},
+		DefaultSorts: []Sort{{By: 1, Desc: true}},
+	})
+
+	t.Run("primary key", func(t *testing.T) {
+		where := &Where{ID: "a"}
+		sql := where.Where(db.NewSelect().Model((*uuidModel)(nil))).String()
+		if !strings.Contains(sql, `"uuid_model"."uuid" = 'a'`) {
+			t.Errorf("Where() should use the primary key, got %s", sql)
+		}
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		where := &Where{ID: "a", HasFlags: []int{2}}
+		query := db.NewSelect().Model((*registeredModel)(nil))
+		sql := where.Select(where.Where(query)).String()
+
+		for _, want := range []string{`"registered_model"."external_id" = 'a'`, `"registered_model"."flags_mask" & 2 = 2`, `ORDER BY "created" desc`} {
+			if !strings.Contains(sql, want) {
+				t.Errorf("got %s, want it to contain %s", sql, want)
+			}
+		}
+	})
+
+	t.Run("explicit over registered", func(t *testing.T) {
+		where := &Where{ID: "a", IDCol: "id"}
+		sql := CondApply(where.Condition()).Delete(db.NewDelete().Model((*registeredModel)(nil))).String()
+		if !strings.Contains(sql, `"registered_model"."id" = 'a'`) {
+			t.Errorf("Where() should prefer its own columns, got %s", sql)
 		}
 	})
 }
@@ -273,6 +345,85 @@ func TestWhere_Select(t *testing.T) {
 	})
 }
 
+func TestWhere_Sorts(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	type testModel struct {
+		bun.BaseModel `bun:"table:test"`
+		ID            string `bun:"id,pk"`
+		Status        string `bun:"status"`
+		CreatedAt     string `bun:"created_at"`
+	}
+
+	order := Order{1: "status", 2: "created_at"}
+
+	tests := []struct {
+		name  string
+		where Where
+		want  string
+	}{
+		{
+			name:  "multiple keys",
+			where: Where{Order: order, Sorts: []Sort{{By: 1}, {By: 2, Desc: true}}},
+			want:  `ORDER BY "status" asc, "created_at" desc`,
+		},
+		{
+			name:  "nulls",
+			where: Where{Order: order, Sorts: []Sort{{By: 1, Nulls: NullsFirst}, {By: 2, Desc: true, Nulls: NullsLast}}},
+			want:  `ORDER BY "status" asc NULLS FIRST, "created_at" desc NULLS LAST`,
+		},
+		{
+			name:  "sorts over sort by",
+			where: Where{Order: order, SortBy: 1, Sorts: []Sort{{By: 2}}},
+			want:  `ORDER BY "created_at" asc`,
+		},
+		{
+			name:  "default",
+			where: Where{Order: order, SortBy: 999, DefaultSorts: []Sort{{By: 2, Desc: true}}},
+			want:  `ORDER BY "created_at" desc`,
+		},
+		{
+			name:  "default without sort by",
+			where: Where{Order: order, DefaultSorts: []Sort{{By: 2, Desc: true}}},
+			want:  `ORDER BY "created_at" desc`,
+		},
+		{
+			name:  "sort by over default",
+			where: Where{Order: order, SortBy: 1, DefaultSorts: []Sort{{By: 2, Desc: true}}},
+			want:  `ORDER BY "status" asc`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := tt.where.Select(db.NewSelect().Model((*testModel)(nil))).String()
+			if !strings.HasSuffix(sql, tt.want) {
+				t.Errorf("got %s, want it to end with %s", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, where := range []Where{
+			{Order: order, Sorts: []Sort{{By: 3}}},
+			{Order: order, Sorts: []Sort{{By: 1}, {By: 1, Desc: true}}},
+			{Order: order, DefaultSorts: []Sort{{By: 3}}},
+			{Order: order, Sorts: []Sort{{By: 1, Nulls: 5}}},
+			{Order: Order{0: "id", 1: "status"}, DefaultSorts: []Sort{{By: 1, Desc: true}}},
+		} {
+			if err := where.ValidateSorts(); !errors.Is(err, ErrInvalidSort) {
+				t.Errorf("ValidateSorts() = %v, want ErrInvalidSort", err)
+			}
+
+			result := where.Select(db.NewSelect().Model((*testModel)(nil)))
+			if _, err := result.AppendQuery(db.Formatter(), nil); !errors.Is(err, ErrInvalidSort) {
+				t.Errorf("Select() error = %v, want ErrInvalidSort", err)
+			}
+		}
+	})
+}
+
 func TestOrderAsc(t *testing.T) {
 	result := OrderAsc("name")
 	expected := "name asc"
@@ -323,3 +474,49 @@ func TestOrder(t *testing.T) {
 		t.Error("Order map should store values correctly")
 	}
 }
+
+func TestWhereOf(t *testing.T) {
+	db := newTestDB()
+	defer db.Close()
+
+	type intModel struct {
+		bun.BaseModel `bun:"table:ints"`
+		ID            int64 `bun:"id,pk"`
+	}
+
+	t.Run("typed IDs", func(t *testing.T) {
+		where := &WhereOf[int64]{ID: 1, IDs: []int64{2, 3}, NotInIDs: []int64{4}}
+		sql := where.Where(db.NewSelect().Model((*intModel)(nil))).String()
+
+		for _, want := range []string{`"int_model"."id" = 1`, `"int_model"."id" IN (2, 3)`, `"int_model"."id" NOT IN (4)`} {
+			if !strings.Contains(sql, want) {
+				t.Errorf("Where() = %s, want it to contain %s", sql, want)
+			}
+		}
+	})
+
+	t.Run("zero ID", func(t *testing.T) {
+		where := &WhereOf[int64]{}
+		sql := where.Where(db.NewSelect().Model((*intModel)(nil))).String()
+		if strings.Contains(sql, "WHERE") {
+			t.Errorf("Where() should skip zero ID, got %s", sql)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var where WhereOf[int64]
+		if err := json.Unmarshal([]byte(`{"id":1,"ids":[2,3]}`), &where); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if where.ID != 1 || len(where.IDs) != 2 || where.IDs[1] != 3 {
+			t.Errorf("Unmarshal() = %+v", where)
+		}
+	})
+
+	t.Run("UseWhere", func(t *testing.T) {
+		sql := UseWhere(WhereOf[int64]{IDs: []int64{5}})(db.NewSelect().Model((*intModel)(nil))).String()
+		if !strings.Contains(sql, `"int_model"."id" IN (5)`) {
+			t.Errorf("UseWhere() = %s", sql)
+		}
+	})
+}